@@ -2,21 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
 	"time"
 
+	"id-generator/internal/allocator"
+	"id-generator/internal/cache"
 	master_server "id-generator/internal/master-server"
+	"id-generator/internal/metrics"
 	"id-generator/internal/pb"
+	"id-generator/internal/servers"
+	"id-generator/internal/tracing"
+	"id-generator/pkg/idformat"
 
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type grpcServerInternal struct {
@@ -25,7 +39,11 @@ type grpcServerInternal struct {
 }
 
 var (
-	env = flag.String("env", ".env", "Env(s) file to load variables from. E.g. .env or .env1,.env2")
+	env            = flag.String("env", ".env", "Env(s) file to load variables from. E.g. .env or .env1,.env2")
+	grpcReflection = flag.Bool("grpc-reflection", false, "Enable gRPC server reflection, e.g. for grpcurl")
+
+	redisFlags   = cache.RegisterFlags(flag.CommandLine)
+	tracingFlags = tracing.RegisterFlags(flag.CommandLine)
 )
 
 func main() {
@@ -36,11 +54,49 @@ func main() {
 		log.Print("failed to load env file")
 	}
 
+	shutdownTracing, err := tracingFlags.Init("id-generator-master")
+	if err != nil {
+		log.Fatalf("error in tracing configuration: %v", err)
+	}
+
+	redisConfig, err := redisFlags.Config()
+	if err != nil {
+		log.Fatalf("error in redis configuration: %v", err)
+	}
+
+	if err := cache.Init(redisConfig); err != nil {
+		log.Fatalf("error in initializing redis client: %v", err)
+	}
+
+	clock, err := idformat.ParseClock(os.Getenv("ID_EPOCH"), os.Getenv("ID_TIMESTAMP_RESOLUTION"))
+	if err != nil {
+		log.Fatalf("error in timestamp configuration: %v", err)
+	}
+
+	layout, err := idformat.ParseLayout(
+		os.Getenv("ID_TIMESTAMP_DIGITS"),
+		os.Getenv("ID_SYS_TYPE_DIGITS"),
+		os.Getenv("FREE_DIGITS_FOR_IDS"),
+		os.Getenv("MAX_ALLOWED_MULTIPLIER"),
+		clock,
+		nil,
+	)
+	if err != nil {
+		log.Fatalf("error in id layout configuration: %v", err)
+	}
+
+	leaser, err := allocator.NewRedisLeaser(
+		os.Getenv("REDIS_WORKER_KEY"), os.Getenv("SNOWFLAKE_WORKERS"), os.Getenv("SNOWFLAKE_LEASE_TTL"),
+	)
+	if err != nil {
+		log.Fatalf("error in worker ids configuration: %v", err)
+	}
+
 	masterServerCache, err := master_server.NewMasterServer(
 		os.Getenv("REDIS_COUNTER_KEY"),
 		os.Getenv("REDIS_TIMESTAMP_KEY"),
-		os.Getenv("MAX_ALLOWED_MULTIPLIER"),
-		os.Getenv("FREE_DIGITS_FOR_IDS"),
+		layout,
+		leaser,
 	)
 	if err != nil {
 		log.Fatalf("error in initializing master server: %v", err)
@@ -56,10 +112,30 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(metrics.UnaryServerInterceptor()),
+	)
 	pb.RegisterOrchestratorServer(grpcServer, &grpcServerInternal{
 		masterServerCache: masterServerCache,
 	})
+
+	// master server is reported as not serving while Redis is unreachable
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	servers.WatchHealth(healthCtx, healthServer, func(ctx context.Context) error {
+		if err := cache.Dragonfly.RawClient.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("redis is unreachable: %v", err)
+		}
+
+		return nil
+	}, pb.Orchestrator_ServiceDesc.ServiceName)
+
+	if *grpcReflection {
+		reflection.Register(grpcServer)
+	}
+
 	log.Printf("grpc server listening at %v", lis.Addr())
 
 	go func() {
@@ -68,28 +144,105 @@ func main() {
 		}
 	}()
 
-	masterServerCache.LoadRedisScript()
+	metricsPort := os.Getenv("MASTER_SERVER_METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "3501"
+	}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{
+		Addr:    fmt.Sprintf(":%s", metricsPort),
+		Handler: metricsMux,
+	}
+	log.Printf("metrics server listening at %v", metricsServer.Addr)
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
 
+	stopHealth()
+	healthServer.Shutdown()
 	grpcServer.GracefulStop()
+	metricsServer.Shutdown(context.Background())
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("failed to flush spans: %v", err)
+	}
 }
 
-func (s *grpcServerInternal) GetMultiplierAndTimestamp(_ context.Context, _ *pb.MultiplierAndTimestampRequest) (*pb.MultiplierAndTimestampReply, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+func (s *grpcServerInternal) GetMultiplierAndTimestamp(ctx context.Context, req *pb.MultiplierAndTimestampRequest) (*pb.MultiplierAndTimestampReply, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
 	defer cancel()
 
-	multiplier, timestamp, err := s.masterServerCache.GetMultiplierAndTimestamp(ctx)
+	multiplier, timestamp, err := s.masterServerCache.GetMultiplierAndTimestamp(ctx, req.GetNamespace())
 	if err != nil {
 		return nil, err
 	}
 
+	clock := s.masterServerCache.Clock()
+
 	return &pb.MultiplierAndTimestampReply{
-			Timestamp:  timestamp,
-			Multiplier: multiplier,
+			Timestamp:            timestamp,
+			Multiplier:           multiplier,
+			EpochMs:              clock.Epoch.UnixMilli(),
+			ResolutionMs:         clock.Resolution.Milliseconds(),
+			MaxAllowedMultiplier: int32(s.masterServerCache.MaxAllowedMultiplier()),
 		},
 		nil
 }
+
+// AcquireWorkerId reports that all worker ids are leased with ResourceExhausted code, so the node retries later.
+func (s *grpcServerInternal) AcquireWorkerId(ctx context.Context, req *pb.AcquireWorkerIdRequest) (*pb.WorkerIdLease, error) {
+	lease, err := s.masterServerCache.Leaser().AcquireWorkerId(ctx, req.GetOwner())
+	if errors.Is(err, allocator.ErrNoFreeWorkerId) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	return toWorkerIdLease(lease), nil
+}
+
+// RenewWorkerId reports lost lease with FailedPrecondition code, so the node acquires another worker id.
+func (s *grpcServerInternal) RenewWorkerId(ctx context.Context, req *pb.WorkerIdRequest) (*pb.WorkerIdLease, error) {
+	leaser := s.masterServerCache.Leaser()
+
+	err := leaser.RenewWorkerId(ctx, req.GetOwner(), int(req.GetWorkerId()))
+	if errors.Is(err, allocator.ErrLeaseLost) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toWorkerIdLease(allocator.WorkerLease{WorkerId: int(req.GetWorkerId()), Workers: leaser.Workers(), TTL: leaser.TTL()}), nil
+}
+
+func (s *grpcServerInternal) ReleaseWorkerId(ctx context.Context, req *pb.WorkerIdRequest) (*pb.ReleaseWorkerIdReply, error) {
+	if err := s.masterServerCache.Leaser().ReleaseWorkerId(ctx, req.GetOwner(), int(req.GetWorkerId())); err != nil {
+		return nil, err
+	}
+
+	return &pb.ReleaseWorkerIdReply{}, nil
+}
+
+func toWorkerIdLease(lease allocator.WorkerLease) *pb.WorkerIdLease {
+	return &pb.WorkerIdLease{
+		WorkerId: int32(lease.WorkerId),
+		Workers:  int32(lease.Workers),
+		TtlMs:    lease.TTL.Milliseconds(),
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"id-generator/internal/ledger"
+)
+
+// newLedger returns ledger of blocks given out to the node, or nil if it is disabled.
+func newLedger(ledgerType string) (ledger.Ledger, error) {
+	switch ledgerType {
+	case "":
+		return nil, nil
+	case "redis":
+		return ledger.NewRedis(orDefault(os.Getenv("REDIS_LEDGER_KEY"), "allocation-ledger"), os.Getenv("REDIS_LEDGER_MAX_LEN"))
+	case "file":
+		return ledger.NewFile(*ledgerFile)
+	}
+
+	return nil, fmt.Errorf("unknown ledger: %s", ledgerType)
+}
+
+// nodeName returns --node-name if it is set, otherwise hostname with the grpc port.
+func nodeName() string {
+	if *nodeNameFlag != "" {
+		return *nodeNameFlag
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", hostname, *grpcPort)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"id-generator/internal/allocator"
+	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/internal/ledger"
+	"id-generator/internal/metrics"
+	"id-generator/internal/pb"
+	"id-generator/pkg/idformat"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// leaseTimeout limits acquiring of worker id on start.
+const leaseTimeout = 5 * time.Second
+
+// namespaceConfig is an item of NAMESPACES_FILE. Empty fields of layout are taken from .env variables.
+type namespaceConfig struct {
+	Name                 string `json:"name"`
+	Prefix               string `json:"prefix"`
+	RedisCounterKey      string `json:"redis_counter_key"`
+	RedisTimestampKey    string `json:"redis_timestamp_key"`
+	TimestampDigits      int    `json:"timestamp_digits"`
+	SysTypeDigits        int    `json:"sys_type_digits"`
+	FreeDigits           int    `json:"free_digits"`
+	MaxAllowedMultiplier int    `json:"max_allowed_multiplier"`
+	SysTypesFile         string `json:"sys_types_file"`
+	ObfuscationKey       string `json:"obfuscation_key"`
+	CheckDigit           string `json:"check_digit"`
+}
+
+// loadNamespaces returns the default namespace configured with .env variables
+// and named namespaces of NAMESPACES_FILE if it is set. In snowflake mode it returns the lease of worker id too,
+// all namespaces share it. Blocks of all namespaces are recorded to the ledger if it is set.
+func loadNamespaces(
+	allocatorType string, clock idformat.Clock, allocationLedger ledger.Ledger,
+) ([]generator_storage.Namespace, *allocator.Lease, error) {
+	configs := []namespaceConfig{{
+		RedisCounterKey:   os.Getenv("REDIS_COUNTER_KEY"),
+		RedisTimestampKey: os.Getenv("REDIS_TIMESTAMP_KEY"),
+		SysTypesFile:      os.Getenv("SYS_TYPES_FILE"),
+	}}
+
+	if namespacesFile := os.Getenv("NAMESPACES_FILE"); namespacesFile != "" {
+		data, err := os.ReadFile(namespacesFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read namespaces file: %v", err)
+		}
+
+		var namedConfigs []namespaceConfig
+		if err := json.Unmarshal(data, &namedConfigs); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse namespaces file: %v", err)
+		}
+
+		configs = append(configs, namedConfigs...)
+	}
+
+	layouts := make([]idformat.Layout, len(configs))
+	for i, config := range configs {
+		var err error
+		if layouts[i], err = config.layout(clock); err != nil {
+			return nil, nil, fmt.Errorf("error in id layout of namespace %q: %v", config.Name, err)
+		}
+	}
+
+	// only ids of the default namespace can be packed into int64, requests for them fail while its timestamps overflow
+	if err := layouts[0].ValidatePack(); err != nil {
+		log.Printf("int64 ids aren't given out: %v", err)
+	}
+
+	var master *allocator.Master
+	if *masterAddr != "" {
+		conn, err := grpc.NewClient(
+			*masterAddr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to master's grpc server (%s): %v", *masterAddr, err)
+		}
+
+		master = allocator.NewMaster(
+			pb.NewOrchestratorClient(conn), *masterTimeout, *masterRetries, clock, layouts[0].MaxAllowedMultiplier,
+		)
+	}
+
+	var lease *allocator.Lease
+	if allocatorType == "snowflake" {
+		var err error
+		if lease, err = acquireLease(master); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	redisKeys := make(map[string]string)
+	namespaces := make([]generator_storage.Namespace, len(configs))
+	for i, config := range configs {
+		layout := layouts[i]
+
+		// blocks of master server are checked against multiplier of the default layout
+		if master != nil && layout.MaxAllowedMultiplier != layouts[0].MaxAllowedMultiplier {
+			return nil, nil, fmt.Errorf(
+				"namespace %q must use MAX_ALLOWED_MULTIPLIER of the default layout of the node with --master-addr", config.Name,
+			)
+		}
+
+		// storages of named namespaces are created on their first request, so their keys are checked right away
+		if master == nil && allocatorType == "redis" {
+			if config.RedisCounterKey == "" || config.RedisTimestampKey == "" {
+				keys := "REDIS_COUNTER_KEY and REDIS_TIMESTAMP_KEY"
+				if config.Name != "" {
+					keys = "redis_counter_key and redis_timestamp_key"
+				}
+
+				return nil, nil, fmt.Errorf("namespace %q must have %s with redis allocator", config.Name, keys)
+			}
+
+			for _, key := range []string{config.RedisCounterKey, config.RedisTimestampKey} {
+				if other, ok := redisKeys[key]; ok {
+					return nil, nil, fmt.Errorf("namespaces %q and %q use the same redis key %s", other, config.Name, key)
+				}
+				redisKeys[key] = config.Name
+			}
+		}
+
+		allocators, err := newAllocatorFactory(allocatorType, clock, master, lease, config, layout)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		allocators = metrics.InstrumentedFactory(allocators, config.Name)
+
+		if allocationLedger != nil {
+			allocators = ledger.AuditedFactory(allocators, allocationLedger, nodeName(), config.Name)
+		}
+
+		namespaces[i] = generator_storage.Namespace{
+			Name:            config.Name,
+			Prefix:          config.Prefix,
+			Layout:          layout,
+			Allocators:      allocators,
+			PercentWhenFill: *percentWhenFill,
+		}
+	}
+
+	return namespaces, lease, nil
+}
+
+func (c namespaceConfig) layout(clock idformat.Clock) (idformat.Layout, error) {
+	sysTypes := idformat.DefaultSysTypes
+	if c.SysTypesFile != "" {
+		var err error
+		if sysTypes, err = idformat.LoadSysTypes(c.SysTypesFile); err != nil {
+			return idformat.Layout{}, fmt.Errorf("error in sys types configuration: %v", err)
+		}
+	}
+
+	layout, err := idformat.ParseLayout(
+		orEnv(c.TimestampDigits, "ID_TIMESTAMP_DIGITS"),
+		orEnv(c.SysTypeDigits, "ID_SYS_TYPE_DIGITS"),
+		orEnv(c.FreeDigits, "FREE_DIGITS_FOR_IDS"),
+		orEnv(c.MaxAllowedMultiplier, "MAX_ALLOWED_MULTIPLIER"),
+		clock,
+		sysTypes,
+	)
+	if err != nil {
+		return idformat.Layout{}, err
+	}
+
+	obfuscationKey := c.ObfuscationKey
+	if obfuscationKey == "" {
+		obfuscationKey = os.Getenv("ID_OBFUSCATION_KEY")
+	}
+
+	if obfuscationKey != "" {
+		if layout.Obfuscator, err = idformat.NewObfuscator(obfuscationKey); err != nil {
+			return idformat.Layout{}, err
+		}
+	}
+
+	checkDigit := c.CheckDigit
+	if checkDigit == "" {
+		checkDigit = os.Getenv("ID_CHECK_DIGIT")
+	}
+
+	if layout.CheckDigit, err = idformat.ParseCheckDigit(checkDigit); err != nil {
+		return idformat.Layout{}, err
+	}
+
+	return layout, layout.Validate()
+}
+
+// orDefault returns the value if it is set, otherwise the default one.
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// orEnv returns the value if it is set, otherwise value of .env variable.
+func orEnv(value int, envKey string) string {
+	if value != 0 {
+		return strconv.Itoa(value)
+	}
+
+	return os.Getenv(envKey)
+}
+
+// acquireLease leases worker id of snowflake mode through master server if it is set, otherwise in Redis.
+func acquireLease(master *allocator.Master) (*allocator.Lease, error) {
+	var leaser allocator.Leaser = master
+	if master == nil {
+		redisLeaser, err := allocator.NewRedisLeaser(
+			os.Getenv("REDIS_WORKER_KEY"), os.Getenv("SNOWFLAKE_WORKERS"), os.Getenv("SNOWFLAKE_LEASE_TTL"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error in worker ids configuration: %v", err)
+		}
+
+		leaser = redisLeaser
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+
+	lease, err := allocator.AcquireLease(ctx, leaser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lease worker id: %v", err)
+	}
+
+	return lease, nil
+}
+
+// newAllocatorFactory returns factory of allocators of counter namespaces of sys types of the namespace.
+// Through master server counters of named namespace are kept under "<namespace>/" and "<namespace>/<counter namespace>".
+func newAllocatorFactory(
+	allocatorType string, clock idformat.Clock, master *allocator.Master, lease *allocator.Lease, config namespaceConfig,
+	layout idformat.Layout,
+) (generator_storage.AllocatorFactory, error) {
+	maxAllowedMultiplier := strconv.Itoa(layout.MaxAllowedMultiplier)
+
+	if lease != nil {
+		// blocks of every counter namespace are given out by the leased worker id, master server isn't asked for them
+		return func(string) (generator_storage.Allocator, error) {
+			return allocator.NewSnowflake(lease, maxAllowedMultiplier, clock)
+		}, nil
+	}
+
+	if master != nil {
+		return func(counterNamespace string) (generator_storage.Allocator, error) {
+			if config.Name == "" {
+				return master.WithNamespace(counterNamespace), nil
+			}
+
+			// counter namespaces have no slashes, so counters of named namespaces never match the default ones
+			return master.WithNamespace(config.Name + "/" + counterNamespace), nil
+		}, nil
+	}
+
+	switch allocatorType {
+	case "redis":
+		return func(counterNamespace string) (generator_storage.Allocator, error) {
+			return allocator.NewRedis(
+				allocator.NamespacedKey(config.RedisCounterKey, counterNamespace),
+				allocator.NamespacedKey(config.RedisTimestampKey, counterNamespace),
+				maxAllowedMultiplier,
+				clock,
+			)
+		}, nil
+	case "local":
+		return func(string) (generator_storage.Allocator, error) {
+			return allocator.NewLocal(maxAllowedMultiplier, clock)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown allocator: %s", allocatorType)
+}
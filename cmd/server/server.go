@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
@@ -8,9 +9,14 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
+	"id-generator/internal/cache"
 	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/internal/metrics"
 	"id-generator/internal/servers"
+	"id-generator/internal/tracing"
+	"id-generator/pkg/idformat"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +26,18 @@ var (
 	grpcPort        = flag.Int("grpc-port", 3001, "Port to run grpc server")
 	env             = flag.String("env", ".env", "Env(s) file to load variables from. E.g. .env or .env1,.env2")
 	percentWhenFill = flag.Float64("when-fill", 0.3, "Percentage when channel of generated ids make a new request for multiplier. E.g. 0.3 = 30%")
+	allocatorType   = flag.String("allocator", "redis", "Backend to allocate blocks of ids from: redis, snowflake (leased worker id, through master server if --master-addr is set) or local (in-process, single node only)")
+	masterAddr      = flag.String("master-addr", "", "Address of master server, e.g. localhost:3500. When set, blocks of ids are allocated through it instead of --allocator, in snowflake mode it leases worker ids")
+	masterTimeout   = flag.Duration("master-timeout", 500*time.Millisecond, "Timeout of one request to master server")
+	masterRetries   = flag.Int("master-retries", 3, "Number of retries of failed request to master server")
+	ledgerType      = flag.String("ledger", "", "Ledger to record every block given out to the node to: redis (stream shared by all nodes) or file (JSON lines), disabled if empty")
+	ledgerFile      = flag.String("ledger-file", "allocation-ledger.jsonl", "File of --ledger=file")
+	nodeNameFlag    = flag.String("node-name", "", "Name of the node in the ledger (default hostname:grpc-port)")
+	shutdownTimeout = flag.Duration("shutdown-timeout", 10*time.Second, "Timeout of returning unused buffered ids to the allocator on shutdown")
+	grpcReflection  = flag.Bool("grpc-reflection", false, "Enable gRPC server reflection, e.g. for grpcurl")
+
+	redisFlags   = cache.RegisterFlags(flag.CommandLine)
+	tracingFlags = tracing.RegisterFlags(flag.CommandLine)
 )
 
 type Server interface {
@@ -35,14 +53,46 @@ func main() {
 		log.Print("failed to load env file(s)")
 	}
 
-	storage, err := generator_storage.NewStorage(
-		os.Getenv("REDIS_COUNTER_KEY"),
-		os.Getenv("REDIS_TIMESTAMP_KEY"),
-		os.Getenv("MAX_ALLOWED_MULTIPLIER"),
-		os.Getenv("FREE_DIGITS_FOR_IDS"),
-		*percentWhenFill,
-	)
+	shutdownTracing, err := tracingFlags.Init("id-generator")
 	if err != nil {
+		log.Fatalf("error in tracing configuration: %v", err)
+	}
+
+	redisConfig, err := redisFlags.Config()
+	if err != nil {
+		log.Fatalf("error in redis configuration: %v", err)
+	}
+
+	if err := cache.Init(redisConfig); err != nil {
+		log.Fatalf("error in initializing redis client: %v", err)
+	}
+
+	clock, err := idformat.ParseClock(os.Getenv("ID_EPOCH"), os.Getenv("ID_TIMESTAMP_RESOLUTION"))
+	if err != nil {
+		log.Fatalf("error in timestamp configuration: %v", err)
+	}
+
+	allocationLedger, err := newLedger(*ledgerType)
+	if err != nil {
+		log.Fatalf("error in ledger configuration: %v", err)
+	}
+
+	namespaces, lease, err := loadNamespaces(*allocatorType, clock, allocationLedger)
+	if err != nil {
+		log.Fatalf("error in namespaces configuration: %v", err)
+	}
+
+	registry, err := generator_storage.NewRegistry(namespaces)
+	if err != nil {
+		log.Fatalf("error in initializing storage server: %v", err)
+	}
+
+	if err := metrics.RegisterStorage(registry); err != nil {
+		log.Fatalf("error in registering metrics: %v", err)
+	}
+
+	// the default namespace is started right away, named ones are started on their first request
+	if _, err := registry.Storage(""); err != nil {
 		log.Fatalf("error in initializing storage server: %v", err)
 	}
 
@@ -51,8 +101,8 @@ func main() {
 	wg.Add(2)
 
 	servers := []Server{
-		servers.NewGrpcServer(*grpcPort, storage),
-		servers.NewHttpServer(*httpPort, storage),
+		servers.NewGrpcServer(*grpcPort, registry, allocationLedger, *grpcReflection),
+		servers.NewHttpServer(*httpPort, registry, allocationLedger),
 	}
 
 	for _, server := range servers {
@@ -72,19 +122,35 @@ func main() {
 	close(shutdown)
 
 	wg.Wait()
-}
 
-// func initStorageWithMasterServer(storage *generator_storage.Storage) {
-// 	MASTER_SERVER_GRPC_PORT := os.Getenv("MASTER_SERVER_GRPC_PORT")
-// 	if MASTER_SERVER_GRPC_PORT == "" {
-// 		log.Fatal("'MASTER_SERVER_GRPC_PORT' variable is undefined")
-// 	}
+	// unused ids are returned after the servers stop, so no request can take them meanwhile
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancelClose()
+
+	if err := registry.Close(closeCtx); err != nil {
+		log.Printf("failed to return unused ids: %v", err)
+	}
+
+	if allocationLedger != nil {
+		if err := allocationLedger.Close(); err != nil {
+			log.Printf("failed to close ledger: %v", err)
+		}
+	}
+
+	if lease != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+		defer cancel()
 
-// 	masterServerGrpcAddr := fmt.Sprintf("%s:%s", "localhost", MASTER_SERVER_GRPC_PORT)
-// 	conn, err := grpc.NewClient(masterServerGrpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-// 	if err != nil {
-// 		log.Fatalf("failed to connect to master's grpc server (%s): %v\n", masterServerGrpcAddr, err)
-// 	}
+		if err := lease.Close(ctx); err != nil {
+			log.Printf("failed to release worker id: %v", err)
+		}
+	}
+
+	// spans of shutdown itself are flushed too
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelTracing()
 
-// 	storage.Init(pb.NewOrchestratorClient(conn))
-// }
+	if err := shutdownTracing(tracingCtx); err != nil {
+		log.Printf("failed to flush spans: %v", err)
+	}
+}
@@ -12,12 +12,22 @@ import (
 	"time"
 
 	"id-generator/internal/pb"
+	"id-generator/internal/tracing"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-var numOfRequestsFlag = flag.Int("requests", 100, "Number of test requests per 1 server")
+var (
+	numOfRequestsFlag = flag.Int("requests", 100, "Number of test requests per 1 server")
+
+	tracingFlags = tracing.RegisterFlags(flag.CommandLine)
+)
+
+// httpClient propagates trace context of requests to the servers.
+var httpClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
 
 const (
 	host = "localhost"
@@ -36,6 +46,12 @@ func main() {
 	flag.Parse()
 	numOfRequests := *numOfRequestsFlag
 
+	shutdownTracing, err := tracingFlags.Init("id-generator-test-client")
+	if err != nil {
+		log.Fatalf("error in tracing configuration: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	grpcClient1, conn1 := initGrpcClient(grpcAddr1)
 	grpcClient2, conn2 := initGrpcClient(grpcAddr2)
 
@@ -145,7 +161,7 @@ func main() {
 func mockHttpRequest(httpAddr string) string {
 	httpIpAndPort := httpAddr[7:]
 
-	response, err := http.Get(fmt.Sprintf("%s/get-unique-id?sys_type=Clients", httpAddr))
+	response, err := httpClient.Get(fmt.Sprintf("%s/get-unique-id?sys_type=Clients", httpAddr))
 	if err != nil {
 		log.Printf("failed when getting id from http (%s): %v\n", httpIpAndPort, err)
 		return ""
@@ -179,7 +195,11 @@ func mockGrpcRequest(grpcClient pb.GeneratorClient, grpcAddr string) string {
 }
 
 func initGrpcClient(grpcAddr string) (pb.GeneratorClient, *grpc.ClientConn) {
-	conn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(
+		grpcAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 	if err != nil {
 		log.Fatalf("failed to connect to grpc server (%s): %v\n", grpcAddr, err)
 	}
@@ -0,0 +1,53 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+// maxClockRegressionWait limits waiting for the clock, which went backwards, to catch up with timestamps
+// blocks were given out of already. The clock, which is behind for longer, is reported with ClockRegressionError.
+const maxClockRegressionWait = 5 * time.Second
+
+// ClockRegressionError is returned when the clock is behind the high-water mark, the greatest timestamp
+// blocks were given out of, and blocks of the next timestamp are needed. Blocks aren't given out till
+// the clock passes the mark, otherwise blocks of past timestamps could be given out again.
+type ClockRegressionError struct {
+	HighWaterMark int64
+	Timestamp     int64
+	Behind        time.Duration
+}
+
+func (e *ClockRegressionError) Error() string {
+	return fmt.Sprintf(
+		"clock went backwards: timestamp %d is %v behind high-water mark %d, blocks aren't given out till the clock catches up",
+		e.Timestamp, e.Behind, e.HighWaterMark,
+	)
+}
+
+func newClockRegressionError(clock idformat.Clock, now time.Time, highWaterMark int64) *ClockRegressionError {
+	return &ClockRegressionError{
+		HighWaterMark: highWaterMark,
+		Timestamp:     clock.Timestamp(now),
+		Behind:        clock.Time(highWaterMark).Sub(now),
+	}
+}
+
+// waitForTimestamp waits till time of the timestamp, which is ahead of now. It returns ClockRegressionError
+// without waiting if the timestamp is ahead for longer than maxClockRegressionWait.
+func waitForTimestamp(ctx context.Context, clock idformat.Clock, now time.Time, timestamp int64) error {
+	wait := clock.Time(timestamp).Sub(now)
+	if wait > maxClockRegressionWait {
+		return newClockRegressionError(clock, now, timestamp-1)
+	}
+
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("there was an error while waiting for the next timestamp: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,291 @@
+package allocator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"id-generator/internal/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLeaseLost is returned on renewal of worker id, which lease expired or is held by another owner.
+var ErrLeaseLost = errors.New("lease of worker id is lost")
+
+// ErrNoFreeWorkerId is returned on acquiring of worker id, while all of them are leased.
+var ErrNoFreeWorkerId = errors.New("no free worker id")
+
+// WorkerLease is a worker id out of Workers ids leased for TTL.
+type WorkerLease struct {
+	WorkerId int
+	Workers  int
+	TTL      time.Duration
+}
+
+// Leaser leases worker ids, every worker id is held by one owner at a time.
+type Leaser interface {
+	// AcquireWorkerId leases a free worker id, it returns ErrNoFreeWorkerId if all of them are leased.
+	AcquireWorkerId(ctx context.Context, owner string) (WorkerLease, error)
+	// RenewWorkerId extends the lease for TTL, it returns ErrLeaseLost if the owner doesn't hold the worker id anymore.
+	RenewWorkerId(ctx context.Context, owner string, workerId int) error
+	ReleaseWorkerId(ctx context.Context, owner string, workerId int) error
+}
+
+// renewWorkerIdScript extends the lease only if the owner still holds it.
+var renewWorkerIdScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseWorkerIdScript deletes the lease only if the owner still holds it.
+var releaseWorkerIdScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLeaser leases worker ids with keys "<key>:<worker id>" set with SETNX and expiring after TTL.
+type RedisLeaser struct {
+	key     string
+	workers int
+	ttl     time.Duration
+}
+
+// NewRedisLeaser creates leaser of worker ids. Empty values take defaults: key "worker-id", 16 workers and TTL 10s.
+func NewRedisLeaser(key, workersStr, ttlStr string) (*RedisLeaser, error) {
+	if key == "" {
+		key = "worker-id"
+	}
+
+	if workersStr == "" {
+		workersStr = "16"
+	}
+
+	if ttlStr == "" {
+		ttlStr = "10s"
+	}
+
+	workers, err := strconv.Atoi(workersStr)
+	if err != nil || workers < 1 {
+		return nil, fmt.Errorf("SNOWFLAKE_WORKERS must be a positive number")
+	}
+
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil || ttl < time.Second {
+		return nil, fmt.Errorf("SNOWFLAKE_LEASE_TTL must be a duration of at least 1s, e.g. 10s")
+	}
+
+	return &RedisLeaser{key: key, workers: workers, ttl: ttl}, nil
+}
+
+// Workers returns number of worker ids.
+func (r *RedisLeaser) Workers() int {
+	return r.workers
+}
+
+// TTL returns duration of leases.
+func (r *RedisLeaser) TTL() time.Duration {
+	return r.ttl
+}
+
+// AcquireWorkerId leases the first free worker id.
+func (r *RedisLeaser) AcquireWorkerId(ctx context.Context, owner string) (WorkerLease, error) {
+	for workerId := range r.workers {
+		isAcquired, err := cache.Dragonfly.SetUniqueKey(ctx, r.workerKey(workerId), owner, r.ttl)
+		if err != nil {
+			return WorkerLease{}, fmt.Errorf("there was an error while acquiring worker id: %w", err)
+		}
+
+		if isAcquired {
+			return WorkerLease{WorkerId: workerId, Workers: r.workers, TTL: r.ttl}, nil
+		}
+	}
+
+	return WorkerLease{}, fmt.Errorf("%w: all %d worker ids are leased", ErrNoFreeWorkerId, r.workers)
+}
+
+func (r *RedisLeaser) RenewWorkerId(ctx context.Context, owner string, workerId int) error {
+	isRenewed, err := renewWorkerIdScript.Run(
+		ctx, cache.Dragonfly.RawClient, []string{r.workerKey(workerId)}, owner, r.ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return fmt.Errorf("there was an error while renewing worker id: %v", err)
+	}
+
+	if isRenewed == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (r *RedisLeaser) ReleaseWorkerId(ctx context.Context, owner string, workerId int) error {
+	err := releaseWorkerIdScript.Run(ctx, cache.Dragonfly.RawClient, []string{r.workerKey(workerId)}, owner).Err()
+	if err != nil {
+		return fmt.Errorf("there was an error while releasing worker id: %v", err)
+	}
+
+	return nil
+}
+
+func (r *RedisLeaser) workerKey(workerId int) string {
+	return cache.Dragonfly.Key(NamespacedKey(r.key, strconv.Itoa(workerId)))
+}
+
+// Lease keeps a worker id leased: it renews the lease in background every third of TTL and acquires another
+// worker id if the lease is lost. The worker id is valid till TTL passes since the last successful renewal
+// was requested, so the node stops using it before the key of the lease expires.
+type Lease struct {
+	leaser Leaser
+	owner  string
+
+	mu         sync.Mutex
+	lease      WorkerLease
+	acquiredAt time.Time
+	expiresAt  time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// AcquireLease leases a worker id with unique owner of the process and starts its renewal.
+func AcquireLease(ctx context.Context, leaser Leaser) (*Lease, error) {
+	owner, err := newOwner()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lease{leaser: leaser, owner: owner, stop: make(chan struct{}), done: make(chan struct{})}
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	go l.keep()
+
+	return l, nil
+}
+
+// current returns the lease and time it was acquired at, ids of the worker id are unique only after that time.
+func (l *Lease) current() (WorkerLease, time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.expiresAt.IsZero() {
+		return WorkerLease{}, time.Time{}, fmt.Errorf("%w: worker id %d is released or taken by another owner", ErrLeaseLost, l.lease.WorkerId)
+	}
+
+	if !time.Now().Before(l.expiresAt) {
+		return WorkerLease{}, time.Time{}, fmt.Errorf("%w: worker id %d isn't renewed, it expired at %v", ErrLeaseLost, l.lease.WorkerId, l.expiresAt)
+	}
+
+	return l.lease, l.acquiredAt, nil
+}
+
+// Close stops renewal and releases the worker id.
+func (l *Lease) Close(ctx context.Context) error {
+	close(l.stop)
+	<-l.done
+
+	l.mu.Lock()
+	workerId := l.lease.WorkerId
+	l.expiresAt = time.Time{}
+	l.mu.Unlock()
+
+	return l.leaser.ReleaseWorkerId(ctx, l.owner, workerId)
+}
+
+func (l *Lease) acquire(ctx context.Context) error {
+	requestedAt := time.Now()
+
+	lease, err := l.leaser.AcquireWorkerId(ctx, l.owner)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lease = lease
+	l.acquiredAt = requestedAt
+	l.expiresAt = requestedAt.Add(lease.TTL)
+
+	log.Printf("worker id %d of %d is leased for %v", lease.WorkerId, lease.Workers, lease.TTL)
+
+	return nil
+}
+
+func (l *Lease) renew(ctx context.Context) error {
+	requestedAt := time.Now()
+
+	l.mu.Lock()
+	lease := l.lease
+	l.mu.Unlock()
+
+	if err := l.leaser.RenewWorkerId(ctx, l.owner, lease.WorkerId); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expiresAt = requestedAt.Add(lease.TTL)
+
+	return nil
+}
+
+func (l *Lease) keep() {
+	defer close(l.done)
+
+	for {
+		l.mu.Lock()
+		interval := l.lease.TTL / 3
+		l.mu.Unlock()
+
+		select {
+		case <-l.stop:
+			return
+		case <-time.After(interval):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+
+		err := l.renew(ctx)
+		if errors.Is(err, ErrLeaseLost) {
+			// the worker id may be used by another node already
+			l.mu.Lock()
+			l.expiresAt = time.Time{}
+			l.mu.Unlock()
+
+			err = l.acquire(ctx)
+		}
+
+		cancel()
+
+		if err != nil {
+			log.Printf("failed to renew lease of worker id: %v", err)
+		}
+	}
+}
+
+// newOwner returns hostname with random suffix, so restarted process doesn't take over the lease of the old one.
+func newOwner() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate owner of worker id: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+
+	return hostname + "-" + hex.EncodeToString(suffix), nil
+}
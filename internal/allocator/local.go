@@ -0,0 +1,102 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+// Local allocates blocks in process the same way as the lua script does.
+// It is unique only within one process, so it suits tests and single node deployments.
+// If the clock goes backwards, blocks of the last timestamp are given out till they are over,
+// then it waits for the clock to pass the timestamp, see ClockRegressionError.
+// Released blocks are given out again before new ones till the timestamp is over.
+type Local struct {
+	mu                   sync.Mutex
+	multiplier           int32
+	timestamp            int64
+	released             map[int32]struct{}
+	maxAllowedMultiplier int
+	clock                idformat.Clock
+	// now is time.Now, tests replace it to turn the clock back.
+	now func() time.Time
+}
+
+func NewLocal(maxAllowedMultiplierStr string, clock idformat.Clock) (*Local, error) {
+	maxAllowedMultiplier, err := strconv.Atoi(maxAllowedMultiplierStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	}
+
+	return &Local{maxAllowedMultiplier: maxAllowedMultiplier, clock: clock, now: time.Now}, nil
+}
+
+func (l *Local) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	newTimestamp := l.clock.Timestamp(l.now())
+	if newTimestamp > l.timestamp {
+		l.timestamp = newTimestamp
+		l.multiplier = 0
+		l.released = nil
+	}
+
+	for multiplier := range l.released {
+		delete(l.released, multiplier)
+		return multiplier, l.timestamp, nil
+	}
+
+	l.multiplier++
+
+	if int(l.multiplier) > l.maxAllowedMultiplier {
+		// wait for the next timestamp, all blocks of the current one are given out
+		if err := waitForTimestamp(ctx, l.clock, l.now(), l.timestamp+1); err != nil {
+			l.multiplier--
+			return 0, 0, err
+		}
+
+		// the clock could go backwards while waiting
+		now := l.now()
+		if l.clock.Timestamp(now) <= l.timestamp {
+			l.multiplier--
+			return 0, 0, newClockRegressionError(l.clock, now, l.timestamp)
+		}
+
+		newTimestamp = l.clock.Timestamp(now)
+
+		l.timestamp = newTimestamp
+		l.multiplier = 1
+	}
+
+	return l.multiplier, l.timestamp, nil
+}
+
+// ReleaseBlock returns the unused block, so it is given out again while its timestamp is the current one.
+func (l *Local) ReleaseBlock(_ context.Context, multiplier int32, timestamp int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if timestamp != l.timestamp {
+		return fmt.Errorf("timestamp %d of block %d is over", timestamp, multiplier)
+	}
+
+	if multiplier < 1 || multiplier > l.multiplier {
+		return fmt.Errorf("block %d of timestamp %d isn't given out", multiplier, timestamp)
+	}
+
+	if _, ok := l.released[multiplier]; ok {
+		return fmt.Errorf("block %d of timestamp %d is released already", multiplier, timestamp)
+	}
+
+	if l.released == nil {
+		l.released = make(map[int32]struct{})
+	}
+	l.released[multiplier] = struct{}{}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package allocator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+func TestLocalBlocksOnUniqueness(t *testing.T) {
+	local, err := NewLocal("100", idformat.DefaultClock)
+	if err != nil {
+		t.Fatalf("failed to create local allocator: %v", err)
+	}
+
+	type block struct {
+		multiplier int32
+		timestamp  int64
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		blocks = make(map[block]int)
+	)
+	wg.Add(4)
+
+	for range 4 {
+		go func() {
+			defer wg.Done()
+
+			for range 75 {
+				multiplier, timestamp, err := local.GetMultiplierAndTimestamp(context.Background())
+				if err != nil {
+					t.Errorf("failed to get block: %v", err)
+					return
+				}
+
+				if multiplier < 1 || multiplier > 100 {
+					t.Errorf("multiplier is out of range: %d", multiplier)
+				}
+
+				mu.Lock()
+				blocks[block{multiplier, timestamp}]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for b, count := range blocks {
+		if count > 1 {
+			t.Errorf("block was given out %d times: %+v", count, b)
+		}
+	}
+}
+
+func TestLocalWaitStopsAtDeadline(t *testing.T) {
+	local, err := NewLocal("1", idformat.DefaultClock)
+	if err != nil {
+		t.Fatalf("failed to create local allocator: %v", err)
+	}
+
+	// the clock stands at the start of the timestamp, so the next one is a second ahead
+	start := idformat.DefaultClock.Time(idformat.DefaultClock.Timestamp(time.Now()))
+	local.now = func() time.Time { return start }
+
+	if _, _, err := local.GetMultiplierAndTimestamp(context.Background()); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	// the only block of the timestamp is given out, the next one waits for the next timestamp
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := local.GetMultiplierAndTimestamp(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got: %v", err)
+	}
+}
+
+func TestLocalWithClockGoingBackwards(t *testing.T) {
+	clock, err := idformat.ParseClock("", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	local, err := NewLocal("2", clock)
+	if err != nil {
+		t.Fatalf("failed to create local allocator: %v", err)
+	}
+
+	var behind atomic.Int64
+	local.now = func() time.Time {
+		return time.Now().Add(-time.Duration(behind.Load()))
+	}
+
+	_, highWaterMark, err := local.GetMultiplierAndTimestamp(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	// the last block of the timestamp is still given out, then it waits for the clock to pass the timestamp
+	behind.Store(int64(50 * time.Millisecond))
+
+	multiplier, timestamp, err := local.GetMultiplierAndTimestamp(context.Background())
+	if err != nil || multiplier != 2 || timestamp != highWaterMark {
+		t.Fatalf("unexpected block of the last timestamp: %d %d %v", multiplier, timestamp, err)
+	}
+
+	startedAt := time.Now()
+
+	multiplier, timestamp, err = local.GetMultiplierAndTimestamp(context.Background())
+	if err != nil || multiplier != 1 || timestamp <= highWaterMark {
+		t.Fatalf("unexpected block after the clock went backwards: %d %d %v", multiplier, timestamp, err)
+	}
+
+	if time.Since(startedAt) < 40*time.Millisecond {
+		t.Errorf("block is given out before the clock passed the high-water mark")
+	}
+
+	// the clock is behind for too long to wait
+	local.GetMultiplierAndTimestamp(context.Background())
+	behind.Store(int64(time.Minute))
+
+	var regressionErr *ClockRegressionError
+	if _, _, err := local.GetMultiplierAndTimestamp(context.Background()); !errors.As(err, &regressionErr) {
+		t.Fatalf("expected clock regression error, got: %v", err)
+	}
+
+	if regressionErr.HighWaterMark != timestamp || regressionErr.Behind < 59*time.Second {
+		t.Errorf("unexpected clock regression error: %+v", regressionErr)
+	}
+
+	// blocks are given out again when the clock is back
+	behind.Store(0)
+	if _, newTimestamp, err := local.GetMultiplierAndTimestamp(context.Background()); err != nil || newTimestamp <= timestamp {
+		t.Errorf("unexpected block after the clock caught up: %d %v", newTimestamp, err)
+	}
+}
+
+func TestLocalGivesOutReleasedBlocks(t *testing.T) {
+	clock, err := idformat.ParseClock("", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	local, err := NewLocal("10", clock)
+	if err != nil {
+		t.Fatalf("failed to create local allocator: %v", err)
+	}
+
+	now := time.Now()
+	local.now = func() time.Time { return now }
+
+	for range 2 {
+		if _, _, err := local.GetMultiplierAndTimestamp(context.Background()); err != nil {
+			t.Fatalf("failed to get block: %v", err)
+		}
+	}
+
+	timestamp := clock.Timestamp(now)
+	if err := local.ReleaseBlock(context.Background(), 1, timestamp); err != nil {
+		t.Fatalf("failed to release block: %v", err)
+	}
+
+	if err := local.ReleaseBlock(context.Background(), 2, timestamp-1); err == nil {
+		t.Errorf("expected error for block of past timestamp")
+	}
+
+	if err := local.ReleaseBlock(context.Background(), 1, timestamp); err == nil {
+		t.Errorf("expected error for block released twice")
+	}
+
+	for _, multiplier := range []int32{0, 3} {
+		if err := local.ReleaseBlock(context.Background(), multiplier, timestamp); err == nil {
+			t.Errorf("expected error for block %d, which isn't given out", multiplier)
+		}
+	}
+
+	for _, expected := range []int32{1, 3} {
+		if multiplier, _, err := local.GetMultiplierAndTimestamp(context.Background()); err != nil || multiplier != expected {
+			t.Errorf("expected block %d, got %d %v", expected, multiplier, err)
+		}
+	}
+
+	// released blocks are dropped when the timestamp is over
+	local.ReleaseBlock(context.Background(), 2, timestamp)
+	now = now.Add(time.Millisecond)
+
+	if multiplier, _, err := local.GetMultiplierAndTimestamp(context.Background()); err != nil || multiplier != 1 {
+		t.Errorf("expected the first block of the next timestamp, got %d %v", multiplier, err)
+	}
+}
@@ -0,0 +1,145 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"id-generator/internal/pb"
+	"id-generator/pkg/idformat"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const masterRetryBackoff = 50 * time.Millisecond
+
+// Master allocates blocks through the Orchestrator service of the master server.
+// Every attempt is limited by timeout, failed attempts are retried with growing delay.
+// Blocks are accepted only if master server counts timestamps with the same clock and splits them into
+// the same number of blocks, otherwise tails of ids would overflow FREE_DIGITS_FOR_IDS.
+type Master struct {
+	client               pb.OrchestratorClient
+	timeout              time.Duration
+	retries              int
+	clock                idformat.Clock
+	maxAllowedMultiplier int
+	namespace            string
+}
+
+func NewMaster(
+	client pb.OrchestratorClient, timeout time.Duration, retries int, clock idformat.Clock, maxAllowedMultiplier int,
+) *Master {
+	return &Master{client: client, timeout: timeout, retries: retries, clock: clock, maxAllowedMultiplier: maxAllowedMultiplier}
+}
+
+// WithNamespace returns allocator of the same master server, which gets blocks from counter of the namespace.
+func (m *Master) WithNamespace(namespace string) *Master {
+	namespaced := *m
+	namespaced.namespace = namespace
+
+	return &namespaced
+}
+
+func (m *Master) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	backoff := masterRetryBackoff
+
+	for attempt := 1; ; attempt++ {
+		reply, err := m.getMultiplierAndTimestamp(ctx)
+		if err == nil {
+			if reply.GetEpochMs() != m.clock.Epoch.UnixMilli() || reply.GetResolutionMs() != m.clock.Resolution.Milliseconds() {
+				return 0, 0, fmt.Errorf(
+					"clock of master server (epoch %dms, resolution %dms) doesn't match ID_EPOCH or ID_TIMESTAMP_RESOLUTION",
+					reply.GetEpochMs(), reply.GetResolutionMs(),
+				)
+			}
+
+			if int(reply.GetMaxAllowedMultiplier()) != m.maxAllowedMultiplier {
+				return 0, 0, fmt.Errorf(
+					"MAX_ALLOWED_MULTIPLIER of master server (%d) doesn't match MAX_ALLOWED_MULTIPLIER %d of the node",
+					reply.GetMaxAllowedMultiplier(), m.maxAllowedMultiplier,
+				)
+			}
+
+			return reply.GetMultiplier(), reply.GetTimestamp(), nil
+		}
+
+		if attempt > m.retries || !isRetryable(err) {
+			return 0, 0, fmt.Errorf("could not get data from master server after %d attempt(s): %v", attempt, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return 0, 0, fmt.Errorf("could not get data from master server: %v", err)
+		}
+
+		backoff *= 2
+	}
+}
+
+func (m *Master) getMultiplierAndTimestamp(ctx context.Context) (*pb.MultiplierAndTimestampReply, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	return m.client.GetMultiplierAndTimestamp(ctx, &pb.MultiplierAndTimestampRequest{Namespace: m.namespace})
+}
+
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+
+	return false
+}
+
+// AcquireWorkerId leases worker id through master server, see Leaser. Master server reports that all worker ids
+// are leased with ResourceExhausted code.
+func (m *Master) AcquireWorkerId(ctx context.Context, owner string) (WorkerLease, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	reply, err := m.client.AcquireWorkerId(ctx, &pb.AcquireWorkerIdRequest{Owner: owner})
+	if status.Code(err) == codes.ResourceExhausted {
+		return WorkerLease{}, fmt.Errorf("%w: %s", ErrNoFreeWorkerId, status.Convert(err).Message())
+	}
+
+	if err != nil {
+		return WorkerLease{}, fmt.Errorf("could not acquire worker id from master server: %v", err)
+	}
+
+	return WorkerLease{
+		WorkerId: int(reply.GetWorkerId()),
+		Workers:  int(reply.GetWorkers()),
+		TTL:      time.Duration(reply.GetTtlMs()) * time.Millisecond,
+	}, nil
+}
+
+// RenewWorkerId renews the lease through master server, which reports lost leases with FailedPrecondition code.
+func (m *Master) RenewWorkerId(ctx context.Context, owner string, workerId int) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	_, err := m.client.RenewWorkerId(ctx, &pb.WorkerIdRequest{Owner: owner, WorkerId: int32(workerId)})
+	if status.Code(err) == codes.FailedPrecondition {
+		return ErrLeaseLost
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not renew worker id through master server: %v", err)
+	}
+
+	return nil
+}
+
+func (m *Master) ReleaseWorkerId(ctx context.Context, owner string, workerId int) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	if _, err := m.client.ReleaseWorkerId(ctx, &pb.WorkerIdRequest{Owner: owner, WorkerId: int32(workerId)}); err != nil {
+		return fmt.Errorf("could not release worker id through master server: %v", err)
+	}
+
+	return nil
+}
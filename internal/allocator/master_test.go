@@ -0,0 +1,51 @@
+package allocator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"id-generator/internal/pb"
+	"id-generator/pkg/idformat"
+
+	"google.golang.org/grpc"
+)
+
+// stubOrchestrator replies with the block and configuration of master server.
+type stubOrchestrator struct {
+	pb.OrchestratorClient
+	reply *pb.MultiplierAndTimestampReply
+}
+
+func (s stubOrchestrator) GetMultiplierAndTimestamp(
+	context.Context, *pb.MultiplierAndTimestampRequest, ...grpc.CallOption,
+) (*pb.MultiplierAndTimestampReply, error) {
+	return s.reply, nil
+}
+
+func TestMasterRefusesBlocksOfAnotherConfiguration(t *testing.T) {
+	clock := idformat.DefaultClock
+	reply := &pb.MultiplierAndTimestampReply{
+		Timestamp:            clock.Now(),
+		Multiplier:           1,
+		EpochMs:              clock.Epoch.UnixMilli(),
+		ResolutionMs:         clock.Resolution.Milliseconds(),
+		MaxAllowedMultiplier: 10000,
+	}
+
+	master := NewMaster(stubOrchestrator{reply: reply}, time.Second, 0, clock, 10000)
+	if _, _, err := master.GetMultiplierAndTimestamp(context.Background()); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	// multipliers of master server above 10000 would give out tails beyond FREE_DIGITS_FOR_IDS of the node
+	reply.MaxAllowedMultiplier = 100000
+	if _, _, err := master.GetMultiplierAndTimestamp(context.Background()); err == nil {
+		t.Errorf("expected error of another MAX_ALLOWED_MULTIPLIER of master server")
+	}
+
+	reply.MaxAllowedMultiplier, reply.ResolutionMs = 10000, 1
+	if _, _, err := master.GetMultiplierAndTimestamp(context.Background()); err == nil {
+		t.Errorf("expected error of another clock of master server")
+	}
+}
@@ -0,0 +1,23 @@
+-- KEYS[1] - timestamp of the counter
+-- KEYS[2] - blocks of the timestamp released by stopped nodes
+-- KEYS[3] - counter of blocks of the timestamp
+-- ARGV[1] - multiplier of the released block
+-- ARGV[2] - timestamp of the released block
+-- Returns 1 if the block is released, 0 if blocks of another timestamp are given out already,
+-- -1 if the block isn't given out yet or -2 if it is released already.
+if tonumber(redis.call("GET", KEYS[1])) ~= tonumber(ARGV[2]) then
+    return 0
+end
+
+local multiplier = tonumber(ARGV[1])
+if multiplier < 1 or multiplier > (tonumber(redis.call("GET", KEYS[3])) or 0) then
+    return -1
+end
+
+if redis.call("LPOS", KEYS[2], ARGV[1]) then
+    return -2
+end
+
+redis.call("RPUSH", KEYS[2], ARGV[1])
+
+return 1
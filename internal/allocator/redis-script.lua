@@ -0,0 +1,79 @@
+-- KEYS[1] - counter of blocks of the timestamp
+-- KEYS[2] - timestamp of the counter
+-- KEYS[3] - high-water mark, the greatest timestamp blocks were given out of, it never goes backwards
+-- KEYS[4] - blocks of the timestamp released by stopped nodes, they are given out again before new ones
+-- ARGV[1] - max allowed multiplier
+-- ARGV[2] - epoch in units of timestamp resolution
+-- ARGV[3] - "1" if timestamps are in milliseconds, otherwise in seconds
+-- Returns {multiplier, timestamp} or {0, high-water mark, now} if the clock is behind timestamps given out already.
+local function now()
+    local time = redis.call("TIME")
+    if ARGV[3] == "1" then
+        return tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000) - tonumber(ARGV[2])
+    end
+
+    return tonumber(time[1]) - tonumber(ARGV[2])
+end
+
+local newTimestamp = now()
+local timestamp = redis.call("GET", KEYS[2])
+local highWaterMark = redis.call("GET", KEYS[3])
+
+if timestamp and highWaterMark and tonumber(timestamp) < tonumber(highWaterMark) then
+    -- the timestamp is restored from stale data, so is its counter
+    timestamp = false
+end
+
+if timestamp and newTimestamp <= tonumber(timestamp) then
+    local released = redis.call("LPOP", KEYS[4])
+    if released then
+        return {tonumber(released), tonumber(timestamp)}
+    end
+end
+
+local multiplier
+if timestamp then
+    timestamp = tonumber(timestamp)
+    multiplier = redis.call("INCR", KEYS[1])
+else
+    -- the counter of the timestamp is lost, only timestamps after the high-water mark are safe
+    if highWaterMark and newTimestamp <= tonumber(highWaterMark) then
+        return {0, tonumber(highWaterMark), newTimestamp}
+    end
+
+    timestamp = newTimestamp
+    multiplier = 1
+
+    redis.call("SET", KEYS[1], 1)
+    redis.call("DEL", KEYS[4])
+end
+
+if newTimestamp > timestamp then
+    timestamp = newTimestamp
+    multiplier = 1
+
+    redis.call("SET", KEYS[1], 1)
+    redis.call("DEL", KEYS[4])
+end
+
+if multiplier > tonumber(ARGV[1]) then
+    -- blocks of the timestamp are over, but the clock went backwards, so the next timestamp can't be reached here
+    if newTimestamp < timestamp then
+        return {0, timestamp, newTimestamp}
+    end
+
+    while (newTimestamp == timestamp) do
+        newTimestamp = now()
+    end
+
+    timestamp = newTimestamp
+    multiplier = 1
+
+    redis.call("SET", KEYS[1], 1)
+    redis.call("DEL", KEYS[4])
+end
+
+redis.call("SET", KEYS[2], timestamp)
+redis.call("SET", KEYS[3], timestamp)
+
+return {multiplier, timestamp}
@@ -0,0 +1,130 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"id-generator/internal/cache"
+	"id-generator/pkg/idformat"
+
+	_ "embed"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed redis-script.lua
+var redisScriptSource string
+
+var redisScript = redis.NewScript(redisScriptSource)
+
+//go:embed redis-release-script.lua
+var redisReleaseScriptSource string
+
+var redisReleaseScript = redis.NewScript(redisReleaseScriptSource)
+
+// Redis allocates blocks with the lua script, so every node sharing the same keys gets unique blocks.
+// Keys get hash tag of cache.Dragonfly, so cache.Init must be called before NewRedis.
+// The script keeps the high-water mark of timestamps under "<timestamp key>/high-water-mark", so blocks of past
+// timestamps aren't given out again if the clock of Redis goes backwards, e.g. after failover, see ClockRegressionError.
+// Blocks released by stopped nodes are kept under "<timestamp key>/released" till the timestamp is over.
+type Redis struct {
+	redisCounterKey       string
+	redisTimestampKey     string
+	redisHighWaterMarkKey string
+	redisReleasedKey      string
+	maxAllowedMultiplier  int
+	clock                 idformat.Clock
+}
+
+// NewRedis creates the allocator with timestamps of the clock. Keys store timestamps in units of the clock,
+// so different keys must be used after changing ID_EPOCH or ID_TIMESTAMP_RESOLUTION.
+func NewRedis(redisCounterKey, redisTimestampKey, maxAllowedMultiplierStr string, clock idformat.Clock) (*Redis, error) {
+	if redisCounterKey == "" || redisTimestampKey == "" {
+		return nil, fmt.Errorf("redis keys REDIS_COUNTER_KEY or REDIS_TIMESTAMP_KEY must not be empty")
+	}
+
+	maxAllowedMultiplier, err := strconv.Atoi(maxAllowedMultiplierStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	}
+
+	return &Redis{
+		cache.Dragonfly.Key(redisCounterKey),
+		cache.Dragonfly.Key(redisTimestampKey),
+		cache.Dragonfly.Key(redisTimestampKey + "/high-water-mark"),
+		cache.Dragonfly.Key(redisTimestampKey + "/released"),
+		maxAllowedMultiplier,
+		clock,
+	}, nil
+}
+
+// NamespacedKey returns key of the counter namespace. Keys of the default, empty namespace are kept as is.
+func NamespacedKey(key, namespace string) string {
+	if namespace == "" {
+		return key
+	}
+
+	return key + ":" + namespace
+}
+
+// GetMultiplierAndTimestamp runs the script by its sha and loads it first if it is missing on the server.
+// If the clock of Redis is behind the high-water mark, it waits for the clock to catch up.
+func (r *Redis) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	for {
+		multiplier, timestamp, highWaterMark, err := r.runScript(ctx)
+		if err != nil || multiplier != 0 {
+			return multiplier, timestamp, err
+		}
+
+		// the clock of Redis is behind, it is used instead of the clock of the node, which can be out of sync
+		now := r.clock.Time(timestamp)
+		if err := waitForTimestamp(ctx, r.clock, now, highWaterMark+1); err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+func (r *Redis) runScript(ctx context.Context) (multiplier int32, timestamp, highWaterMark int64, err error) {
+	isMillis := 0
+	if r.clock.IsMillis() {
+		isMillis = 1
+	}
+
+	result, err := redisScript.Run(
+		ctx,
+		cache.Dragonfly.RawClient, []string{r.redisCounterKey, r.redisTimestampKey, r.redisHighWaterMarkKey, r.redisReleasedKey},
+		r.maxAllowedMultiplier, r.clock.EpochUnits(), isMillis,
+	).Int64Slice()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("there was an error while getting multiplier or timestamp: %v", err)
+	}
+
+	// multiplier 0 means the clock is behind the high-water mark
+	if result[0] == 0 {
+		return 0, result[2], result[1], nil
+	}
+
+	return int32(result[0]), result[1], 0, nil
+}
+
+// ReleaseBlock returns the unused block, so it is given out again to any node while its timestamp is the current one.
+func (r *Redis) ReleaseBlock(ctx context.Context, multiplier int32, timestamp int64) error {
+	released, err := redisReleaseScript.Run(
+		ctx, cache.Dragonfly.RawClient, []string{r.redisTimestampKey, r.redisReleasedKey, r.redisCounterKey}, multiplier, timestamp,
+	).Int()
+	if err != nil {
+		return fmt.Errorf("there was an error while releasing block: %v", err)
+	}
+
+	switch released {
+	case 0:
+		return fmt.Errorf("timestamp %d of block %d is over", timestamp, multiplier)
+	case -1:
+		return fmt.Errorf("block %d of timestamp %d isn't given out", multiplier, timestamp)
+	case -2:
+		return fmt.Errorf("block %d of timestamp %d is released already", multiplier, timestamp)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package allocator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"id-generator/internal/cache"
+	"id-generator/pkg/idformat"
+)
+
+func TestRedisHighWaterMark(t *testing.T) {
+	ctx := context.Background()
+
+	clock, err := idformat.ParseClock("", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	redisAllocator, err := NewRedis("test-hwm-counter-key", "test-hwm-timestamp-key", "10", clock)
+	if err != nil {
+		t.Fatalf("failed to create redis allocator: %v", err)
+	}
+
+	client := cache.Dragonfly.RawClient
+	keys := []string{redisAllocator.redisCounterKey, redisAllocator.redisTimestampKey, redisAllocator.redisHighWaterMarkKey}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	_, timestamp, err := redisAllocator.GetMultiplierAndTimestamp(ctx)
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	if highWaterMark, _ := client.Get(ctx, redisAllocator.redisHighWaterMarkKey).Int64(); highWaterMark != timestamp {
+		t.Errorf("expected high-water mark %d, got %d", timestamp, highWaterMark)
+	}
+
+	// the timestamp key is lost and the clock is a minute behind timestamps given out already
+	client.Del(ctx, redisAllocator.redisTimestampKey)
+	client.Set(ctx, redisAllocator.redisHighWaterMarkKey, timestamp+60_000, 0)
+
+	var regressionErr *ClockRegressionError
+	if _, _, err := redisAllocator.GetMultiplierAndTimestamp(ctx); !errors.As(err, &regressionErr) {
+		t.Fatalf("expected clock regression error, got: %v", err)
+	}
+
+	if regressionErr.HighWaterMark != timestamp+60_000 {
+		t.Errorf("unexpected clock regression error: %+v", regressionErr)
+	}
+
+	// the timestamp key is restored from stale data and the clock is slightly behind, so it waits
+	highWaterMark := clock.Now() + 50
+	client.Set(ctx, redisAllocator.redisTimestampKey, timestamp, 0)
+	client.Set(ctx, redisAllocator.redisHighWaterMarkKey, highWaterMark, 0)
+
+	startedAt := time.Now()
+
+	multiplier, newTimestamp, err := redisAllocator.GetMultiplierAndTimestamp(ctx)
+	if err != nil || multiplier != 1 || newTimestamp <= highWaterMark {
+		t.Fatalf("unexpected block after the clock went backwards: %d %d %v", multiplier, newTimestamp, err)
+	}
+
+	if time.Since(startedAt) < 40*time.Millisecond {
+		t.Errorf("block is given out before the clock passed the high-water mark")
+	}
+
+	// blocks of the last timestamp are given out while the clock is behind, but not blocks of past ones
+	aheadTimestamp := clock.Now() + 60_000
+	client.Set(ctx, redisAllocator.redisTimestampKey, aheadTimestamp, 0)
+	client.Set(ctx, redisAllocator.redisHighWaterMarkKey, aheadTimestamp, 0)
+	client.Set(ctx, redisAllocator.redisCounterKey, 9, 0)
+
+	if multiplier, _, err := redisAllocator.GetMultiplierAndTimestamp(ctx); err != nil || multiplier != 10 {
+		t.Errorf("expected the last block of the timestamp, got %d %v", multiplier, err)
+	}
+
+	if _, _, err := redisAllocator.GetMultiplierAndTimestamp(ctx); !errors.As(err, &regressionErr) {
+		t.Errorf("expected clock regression error, got: %v", err)
+	}
+}
+
+func TestRedisGivesOutReleasedBlocks(t *testing.T) {
+	ctx := context.Background()
+
+	clock, err := idformat.ParseClock("", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	redisAllocator, err := NewRedis("test-release-counter-key", "test-release-timestamp-key", "10", clock)
+	if err != nil {
+		t.Fatalf("failed to create redis allocator: %v", err)
+	}
+
+	client := cache.Dragonfly.RawClient
+	keys := []string{
+		redisAllocator.redisCounterKey, redisAllocator.redisTimestampKey,
+		redisAllocator.redisHighWaterMarkKey, redisAllocator.redisReleasedKey,
+	}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	// the timestamp is ahead of the clock, so it isn't over during the test
+	timestamp := clock.Now() + 60_000
+	client.Set(ctx, redisAllocator.redisTimestampKey, timestamp, 0)
+	client.Set(ctx, redisAllocator.redisHighWaterMarkKey, timestamp, 0)
+
+	for range 2 {
+		if _, _, err := redisAllocator.GetMultiplierAndTimestamp(ctx); err != nil {
+			t.Fatalf("failed to get block: %v", err)
+		}
+	}
+
+	if err := redisAllocator.ReleaseBlock(ctx, 1, timestamp); err != nil {
+		t.Fatalf("failed to release block: %v", err)
+	}
+
+	if err := redisAllocator.ReleaseBlock(ctx, 2, timestamp-1); err == nil {
+		t.Errorf("expected error for block of past timestamp")
+	}
+
+	if err := redisAllocator.ReleaseBlock(ctx, 1, timestamp); err == nil {
+		t.Errorf("expected error for block released twice")
+	}
+
+	for _, multiplier := range []int32{0, 3} {
+		if err := redisAllocator.ReleaseBlock(ctx, multiplier, timestamp); err == nil {
+			t.Errorf("expected error for block %d, which isn't given out", multiplier)
+		}
+	}
+
+	for _, expected := range []int32{1, 3} {
+		multiplier, blockTimestamp, err := redisAllocator.GetMultiplierAndTimestamp(ctx)
+		if err != nil || multiplier != expected || blockTimestamp != timestamp {
+			t.Errorf("expected block %d of timestamp %d, got %d %d %v", expected, timestamp, multiplier, blockTimestamp, err)
+		}
+	}
+}
+
+func TestRedisLeaserRunsOutOfWorkerIds(t *testing.T) {
+	ctx := context.Background()
+
+	leaser, err := NewRedisLeaser("test-worker-id", "2", "")
+	if err != nil {
+		t.Fatalf("failed to create redis leaser: %v", err)
+	}
+
+	client := cache.Dragonfly.RawClient
+	keys := []string{leaser.workerKey(0), leaser.workerKey(1)}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	for _, owner := range []string{"node-1", "node-2"} {
+		if _, err := leaser.AcquireWorkerId(ctx, owner); err != nil {
+			t.Fatalf("failed to acquire worker id: %v", err)
+		}
+	}
+
+	if _, err := leaser.AcquireWorkerId(ctx, "node-3"); !errors.Is(err, ErrNoFreeWorkerId) {
+		t.Errorf("expected ErrNoFreeWorkerId, got: %v", err)
+	}
+
+	if leaser.TTL() != 10*time.Second {
+		t.Errorf("expected default TTL 10s, got %v", leaser.TTL())
+	}
+}
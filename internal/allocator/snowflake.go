@@ -0,0 +1,127 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+// Snowflake allocates blocks in process like Local, but only multipliers of the worker id leased by the node,
+// so nodes get unique blocks without a round trip to Redis or master server per block. With W worker ids
+// every one gets MAX_ALLOWED_MULTIPLIER / W multipliers of every timestamp: worker id w gives out multipliers
+// from w*M/W+1 to (w+1)*M/W, so ids are timestamp|worker|sequence. Blocks aren't given out while the lease is
+// lost, and a newly leased worker id is used only from the next timestamp, because its previous owner could
+// use it in the current one. Clocks of nodes must not drift apart for more than TTL of the lease. The clock going
+// backwards is handled like in Local.
+type Snowflake struct {
+	mu                   sync.Mutex
+	lease                *Lease
+	workerId             int
+	acquiredAt           time.Time
+	minTimestamp         int64
+	multiplier           int32
+	timestamp            int64
+	maxAllowedMultiplier int
+	clock                idformat.Clock
+	// now is time.Now, tests replace it to turn the clock back.
+	now func() time.Time
+}
+
+func NewSnowflake(lease *Lease, maxAllowedMultiplierStr string, clock idformat.Clock) (*Snowflake, error) {
+	maxAllowedMultiplier, err := strconv.Atoi(maxAllowedMultiplierStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	}
+
+	workerLease, _, err := lease.current()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWorkers(maxAllowedMultiplier, workerLease.Workers); err != nil {
+		return nil, err
+	}
+
+	return &Snowflake{lease: lease, workerId: -1, maxAllowedMultiplier: maxAllowedMultiplier, clock: clock, now: time.Now}, nil
+}
+
+// checkWorkers returns error if multipliers can't be split between worker ids evenly. Otherwise the rest of
+// multipliers would never be given out, and nodes with another number of worker ids would share blocks.
+func checkWorkers(maxAllowedMultiplier, workers int) error {
+	if workers < 1 || maxAllowedMultiplier%workers != 0 {
+		return fmt.Errorf(
+			"MAX_ALLOWED_MULTIPLIER %d must be a multiple of positive number of worker ids %d", maxAllowedMultiplier, workers,
+		)
+	}
+
+	return nil
+}
+
+func (s *Snowflake) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workerLease, acquiredAt, err := s.lease.current()
+	if err != nil {
+		return 0, 0, fmt.Errorf("blocks aren't given out without worker id: %w", err)
+	}
+
+	if workerLease.WorkerId != s.workerId || !acquiredAt.Equal(s.acquiredAt) {
+		// the worker id could be leased again through master server with another SNOWFLAKE_WORKERS
+		if err := checkWorkers(s.maxAllowedMultiplier, workerLease.Workers); err != nil {
+			return 0, 0, err
+		}
+
+		s.workerId = workerLease.WorkerId
+		s.acquiredAt = acquiredAt
+		s.minTimestamp = s.clock.Timestamp(acquiredAt) + 1
+		s.multiplier = 0
+		s.timestamp = 0
+	}
+
+	multipliersPerWorker := int32(s.maxAllowedMultiplier / workerLease.Workers)
+
+	s.multiplier++
+
+	newTimestamp := s.clock.Timestamp(s.now())
+	if newTimestamp > s.timestamp {
+		s.timestamp = newTimestamp
+		s.multiplier = 1
+	}
+
+	if s.multiplier > multipliersPerWorker || s.timestamp < s.minTimestamp {
+		// wait for the next timestamp, all blocks of the worker id in the current one are given out
+		nextTimestamp := max(s.timestamp+1, s.minTimestamp)
+
+		if err := waitForTimestamp(ctx, s.clock, s.now(), nextTimestamp); err != nil {
+			s.multiplier--
+			return 0, 0, err
+		}
+
+		// the lease could be lost while waiting
+		workerLease, acquiredAt, err := s.lease.current()
+		if err != nil {
+			return 0, 0, fmt.Errorf("blocks aren't given out without worker id: %w", err)
+		}
+
+		if workerLease.WorkerId != s.workerId || !acquiredAt.Equal(s.acquiredAt) {
+			return 0, 0, fmt.Errorf("%w: worker id %d was lost while waiting for the next timestamp", ErrLeaseLost, s.workerId)
+		}
+
+		// the clock could go backwards while waiting
+		now := s.now()
+		if s.clock.Timestamp(now) < nextTimestamp {
+			s.multiplier--
+			return 0, 0, newClockRegressionError(s.clock, now, nextTimestamp-1)
+		}
+
+		s.timestamp = s.clock.Timestamp(now)
+		s.multiplier = 1
+	}
+
+	return int32(s.workerId)*multipliersPerWorker + s.multiplier, s.timestamp, nil
+}
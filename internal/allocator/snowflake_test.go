@@ -0,0 +1,191 @@
+package allocator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+// memoryLeaser leases worker ids in memory, leases are lost only with revoke.
+type memoryLeaser struct {
+	mu      sync.Mutex
+	owners  map[int]string
+	workers int
+	ttl     time.Duration
+}
+
+func newMemoryLeaser(workers int, ttl time.Duration) *memoryLeaser {
+	return &memoryLeaser{owners: make(map[int]string), workers: workers, ttl: ttl}
+}
+
+func (m *memoryLeaser) AcquireWorkerId(_ context.Context, owner string) (WorkerLease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for workerId := range m.workers {
+		if _, ok := m.owners[workerId]; !ok {
+			m.owners[workerId] = owner
+			return WorkerLease{WorkerId: workerId, Workers: m.workers, TTL: m.ttl}, nil
+		}
+	}
+
+	return WorkerLease{}, fmt.Errorf("%w: all %d worker ids are leased", ErrNoFreeWorkerId, m.workers)
+}
+
+func (m *memoryLeaser) RenewWorkerId(_ context.Context, owner string, workerId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.owners[workerId] != owner {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (m *memoryLeaser) ReleaseWorkerId(_ context.Context, owner string, workerId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.owners[workerId] == owner {
+		delete(m.owners, workerId)
+	}
+
+	return nil
+}
+
+// revoke gives the worker id to another owner.
+func (m *memoryLeaser) revoke(workerId int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.owners[workerId] = "another-node"
+}
+
+func TestSnowflakeWorkersGetOwnMultipliers(t *testing.T) {
+	clock, err := idformat.ParseClock("", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	leaser := newMemoryLeaser(2, time.Second)
+
+	type block struct {
+		multiplier int32
+		timestamp  int64
+	}
+	blocks := make(map[block]int)
+
+	leases := make([]*Lease, 2)
+	for node := range leases {
+		acquiredAt := clock.Now()
+
+		lease, err := AcquireLease(context.Background(), leaser)
+		if err != nil {
+			t.Fatalf("failed to lease worker id: %v", err)
+		}
+		leases[node] = lease
+
+		snowflake, err := NewSnowflake(lease, "100", clock)
+		if err != nil {
+			t.Fatalf("failed to create snowflake allocator: %v", err)
+		}
+
+		for range 120 {
+			multiplier, timestamp, err := snowflake.GetMultiplierAndTimestamp(context.Background())
+			if err != nil {
+				t.Fatalf("failed to get block: %v", err)
+			}
+
+			// worker id 0 gets multipliers 1-50, worker id 1 gets 51-100
+			if multiplier < int32(node*50+1) || multiplier > int32(node*50+50) {
+				t.Errorf("multiplier %d is out of range of worker id %d", multiplier, node)
+			}
+
+			if timestamp <= acquiredAt {
+				t.Errorf("block of timestamp %d is given out before the next timestamp after lease %d", timestamp, acquiredAt)
+			}
+
+			blocks[block{multiplier, timestamp}]++
+		}
+	}
+
+	for b, count := range blocks {
+		if count > 1 {
+			t.Errorf("block was given out %d times: %+v", count, b)
+		}
+	}
+
+	if err := leases[0].Close(context.Background()); err != nil {
+		t.Fatalf("failed to close lease: %v", err)
+	}
+
+	lease, err := AcquireLease(context.Background(), leaser)
+	if err != nil {
+		t.Fatalf("worker id must be released after close, got: %v", err)
+	}
+
+	lease.Close(context.Background())
+	leases[1].Close(context.Background())
+}
+
+func TestSnowflakeRefusesBlocksWithoutLease(t *testing.T) {
+	leaser := newMemoryLeaser(1, 60*time.Millisecond)
+
+	lease, err := AcquireLease(context.Background(), leaser)
+	if err != nil {
+		t.Fatalf("failed to lease worker id: %v", err)
+	}
+	defer lease.Close(context.Background())
+
+	clock, _ := idformat.ParseClock("", "ms")
+
+	snowflake, err := NewSnowflake(lease, "100", clock)
+	if err != nil {
+		t.Fatalf("failed to create snowflake allocator: %v", err)
+	}
+
+	if _, _, err := snowflake.GetMultiplierAndTimestamp(context.Background()); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	// renewals keep the lease after its TTL
+	time.Sleep(100 * time.Millisecond)
+	if _, _, err := snowflake.GetMultiplierAndTimestamp(context.Background()); err != nil {
+		t.Fatalf("failed to get block with renewed lease: %v", err)
+	}
+
+	// the only worker id is taken by another node, so the lease can't be acquired again
+	leaser.revoke(0)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, _, err := snowflake.GetMultiplierAndTimestamp(context.Background()); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("expected lost lease error, got: %v", err)
+	}
+
+	if _, err := NewSnowflake(lease, "100", clock); err == nil {
+		t.Errorf("expected error for allocator without lease")
+	}
+}
+
+func TestSnowflakeRejectsUnevenSplitOfMultipliers(t *testing.T) {
+	lease, err := AcquireLease(context.Background(), newMemoryLeaser(3, time.Second))
+	if err != nil {
+		t.Fatalf("failed to lease worker id: %v", err)
+	}
+	defer lease.Close(context.Background())
+
+	// 100 multipliers can't be split between 3 worker ids, the last one would be never given out
+	if _, err := NewSnowflake(lease, "100", idformat.DefaultClock); err == nil {
+		t.Errorf("expected error for MAX_ALLOWED_MULTIPLIER, which isn't a multiple of number of worker ids")
+	}
+
+	if _, err := NewSnowflake(lease, "99", idformat.DefaultClock); err != nil {
+		t.Errorf("unexpected error for 33 multipliers of every worker id: %v", err)
+	}
+}
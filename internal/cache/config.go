@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultClusterKeyHashTag = "id-generator"
+
+// Config describes connection to Redis/Dragonfly. Exactly one of modes is used:
+// sentinel when SentinelMasterName is set, cluster when Cluster is true, otherwise single instance.
+type Config struct {
+	// Addrs are addresses of the instance, sentinels or cluster nodes.
+	Addrs              []string
+	Username           string
+	Password           string
+	DB                 int
+	TLS                bool
+	PoolSize           int
+	DialTimeout        time.Duration
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	SentinelMasterName string
+	SentinelPassword   string
+	Cluster            bool
+	// KeyHashTag is added to keys used by one script, so they land in the same cluster slot.
+	KeyHashTag string
+}
+
+var options = []struct {
+	env   string
+	flag  string
+	usage string
+}{
+	{"REDIS_ADDR", "redis-addr", "Comma separated address(es) of Redis/Dragonfly, sentinels or cluster nodes (default localhost:6379)"},
+	{"REDIS_USERNAME", "redis-username", "Redis ACL username"},
+	{"REDIS_PASSWORD", "redis-password", "Redis password"},
+	{"REDIS_DB", "redis-db", "Redis database number, not supported in cluster mode (default 0)"},
+	{"REDIS_TLS", "redis-tls", "Use TLS to connect to Redis: true or false"},
+	{"REDIS_POOL_SIZE", "redis-pool-size", "Maximum number of socket connections (default 10 per CPU)"},
+	{"REDIS_DIAL_TIMEOUT", "redis-dial-timeout", "Timeout for establishing new connections, e.g. 5s"},
+	{"REDIS_READ_TIMEOUT", "redis-read-timeout", "Timeout for socket reads, e.g. 3s"},
+	{"REDIS_WRITE_TIMEOUT", "redis-write-timeout", "Timeout for socket writes, e.g. 3s"},
+	{"REDIS_SENTINEL_MASTER", "redis-sentinel-master", "Name of the master to use Redis Sentinel failover with"},
+	{"REDIS_SENTINEL_PASSWORD", "redis-sentinel-password", "Password of Redis Sentinel"},
+	{"REDIS_CLUSTER", "redis-cluster", "Use Redis Cluster: true or false"},
+	{"REDIS_KEY_HASH_TAG", "redis-key-hash-tag", "Hash tag added to keys, so keys of one script land in the same cluster slot (default id-generator in cluster mode)"},
+}
+
+// Flags are command-line flags overriding env variables of the Config.
+type Flags map[string]*string
+
+// RegisterFlags registers flags of the Config. Values are read with Flags.Config after parsing.
+func RegisterFlags(fs *flag.FlagSet) Flags {
+	flags := make(Flags, len(options))
+	for _, option := range options {
+		flags[option.env] = fs.String(option.flag, "", fmt.Sprintf("%s. Overrides %s env variable", option.usage, option.env))
+	}
+
+	return flags
+}
+
+// ConfigFromEnv reads the Config from env variables only.
+func ConfigFromEnv() (Config, error) {
+	return Flags(nil).Config()
+}
+
+// Config reads the Config from flags, falling back to env variables for flags that are not set.
+func (f Flags) Config() (Config, error) {
+	var (
+		cfg = Config{
+			Addrs:              strings.Split(f.lookup("REDIS_ADDR", "localhost:6379"), ","),
+			Username:           f.lookup("REDIS_USERNAME", ""),
+			Password:           f.lookup("REDIS_PASSWORD", ""),
+			SentinelMasterName: f.lookup("REDIS_SENTINEL_MASTER", ""),
+			SentinelPassword:   f.lookup("REDIS_SENTINEL_PASSWORD", ""),
+			KeyHashTag:         f.lookup("REDIS_KEY_HASH_TAG", ""),
+		}
+		err error
+	)
+
+	if cfg.DB, err = strconv.Atoi(f.lookup("REDIS_DB", "0")); err != nil {
+		return Config{}, fmt.Errorf("failed to convert to int REDIS_DB")
+	}
+
+	if cfg.PoolSize, err = strconv.Atoi(f.lookup("REDIS_POOL_SIZE", "0")); err != nil {
+		return Config{}, fmt.Errorf("failed to convert to int REDIS_POOL_SIZE")
+	}
+
+	if cfg.TLS, err = strconv.ParseBool(f.lookup("REDIS_TLS", "false")); err != nil {
+		return Config{}, fmt.Errorf("failed to convert to bool REDIS_TLS")
+	}
+
+	if cfg.Cluster, err = strconv.ParseBool(f.lookup("REDIS_CLUSTER", "false")); err != nil {
+		return Config{}, fmt.Errorf("failed to convert to bool REDIS_CLUSTER")
+	}
+
+	for _, timeout := range []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"REDIS_DIAL_TIMEOUT", &cfg.DialTimeout},
+		{"REDIS_READ_TIMEOUT", &cfg.ReadTimeout},
+		{"REDIS_WRITE_TIMEOUT", &cfg.WriteTimeout},
+	} {
+		if *timeout.value, err = time.ParseDuration(f.lookup(timeout.env, "0s")); err != nil {
+			return Config{}, fmt.Errorf("failed to convert to duration %s", timeout.env)
+		}
+	}
+
+	if cfg.SentinelPassword != "" && cfg.SentinelMasterName == "" {
+		return Config{}, fmt.Errorf("REDIS_SENTINEL_PASSWORD requires REDIS_SENTINEL_MASTER")
+	}
+
+	if cfg.Cluster && cfg.SentinelMasterName != "" {
+		return Config{}, fmt.Errorf("REDIS_CLUSTER and REDIS_SENTINEL_MASTER can't be used together")
+	}
+
+	if cfg.Cluster && cfg.DB != 0 {
+		return Config{}, fmt.Errorf("REDIS_DB is not supported by Redis Cluster")
+	}
+
+	if cfg.Cluster && cfg.KeyHashTag == "" {
+		cfg.KeyHashTag = defaultClusterKeyHashTag
+	}
+
+	return cfg, nil
+}
+
+func (f Flags) lookup(env, defaultValue string) string {
+	if value := f[env]; value != nil && *value != "" {
+		return *value
+	}
+
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+func newClient(cfg Config) redis.UniversalClient {
+	var tlsConfig *tls.Config
+	if cfg.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	switch {
+	case cfg.SentinelMasterName != "":
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.SentinelMasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConfig,
+			PoolSize:         cfg.PoolSize,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+		})
+	case cfg.Cluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     cfg.PoolSize,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		})
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Addrs[0],
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		TLSConfig:    tlsConfig,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigRejectsInvalidCombinations(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		flags map[string]string
+	}{
+		{"sentinel password without master name", map[string]string{"REDIS_SENTINEL_PASSWORD": "secret"}},
+		{"cluster with sentinel", map[string]string{"REDIS_CLUSTER": "true", "REDIS_SENTINEL_MASTER": "mymaster"}},
+		{"cluster with db", map[string]string{"REDIS_CLUSTER": "true", "REDIS_DB": "1"}},
+		{"db is not a number", map[string]string{"REDIS_DB": "one"}},
+		{"pool size is not a number", map[string]string{"REDIS_POOL_SIZE": "ten"}},
+		{"tls is not a bool", map[string]string{"REDIS_TLS": "yes please"}},
+		{"cluster is not a bool", map[string]string{"REDIS_CLUSTER": "maybe"}},
+		{"timeout without unit", map[string]string{"REDIS_READ_TIMEOUT": "5"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if cfg, err := newFlags(t, tc.flags).Config(); err == nil {
+				t.Errorf("expected error, got config: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg, err := newFlags(t, map[string]string{
+		"REDIS_ADDR":         "node-1:6379,node-2:6379",
+		"REDIS_CLUSTER":      "true",
+		"REDIS_DIAL_TIMEOUT": "5s",
+	}).Config()
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	if len(cfg.Addrs) != 2 || cfg.Addrs[1] != "node-2:6379" {
+		t.Errorf("unexpected addresses: %v", cfg.Addrs)
+	}
+
+	if cfg.KeyHashTag != defaultClusterKeyHashTag {
+		t.Errorf("expected default hash tag in cluster mode, got %q", cfg.KeyHashTag)
+	}
+
+	if cfg.DialTimeout != 5*time.Second {
+		t.Errorf("unexpected dial timeout: %v", cfg.DialTimeout)
+	}
+
+	// sentinel mode with password of sentinels
+	cfg, err = newFlags(t, map[string]string{"REDIS_SENTINEL_MASTER": "mymaster", "REDIS_SENTINEL_PASSWORD": "secret"}).Config()
+	if err != nil || cfg.KeyHashTag != "" {
+		t.Errorf("unexpected sentinel config: %+v %v", cfg, err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	for _, tc := range []struct {
+		hashTag  string
+		expected string
+	}{
+		{"", "counter"},
+		{"id-generator", "{id-generator}:counter"},
+	} {
+		dg := dragonfly{keyHashTag: tc.hashTag}
+		if key := dg.Key("counter"); key != tc.expected {
+			t.Errorf("expected key %q with hash tag %q, got %q", tc.expected, tc.hashTag, key)
+		}
+	}
+}
+
+// newFlags returns Flags with the values. Env variables of the options are cleared, so they don't affect tests.
+func newFlags(t *testing.T, values map[string]string) Flags {
+	flags := make(Flags, len(options))
+	for _, option := range options {
+		t.Setenv(option.env, "")
+
+		value := values[option.env]
+		flags[option.env] = &value
+	}
+
+	return flags
+}
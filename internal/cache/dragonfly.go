@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -14,13 +15,42 @@ const (
 )
 
 type dragonfly struct {
-	RawClient *redis.Client
+	RawClient  redis.UniversalClient
+	keyHashTag string
 }
 
 var Dragonfly = dragonfly{
-	RawClient: redis.NewClient(&redis.Options{
-		Addr: "localhost:6380",
-	}),
+	RawClient: newClient(Config{Addrs: []string{"localhost:6379"}}),
+}
+
+// Init replaces the client of Dragonfly with one configured by cfg. Commands of the client are traced
+// with global tracer provider of OpenTelemetry.
+func Init(cfg Config) error {
+	if len(cfg.Addrs) == 0 || cfg.Addrs[0] == "" {
+		return fmt.Errorf("at least one redis address must be specified")
+	}
+
+	client := newClient(cfg)
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return fmt.Errorf("failed to instrument redis client with tracing: %v", err)
+	}
+
+	oldClient := Dragonfly.RawClient
+
+	Dragonfly.RawClient = client
+	Dragonfly.keyHashTag = cfg.KeyHashTag
+	oldClient.Close()
+
+	return nil
+}
+
+// Key adds configured hash tag to the key, so all keys of the script land in the same cluster slot.
+func (dg *dragonfly) Key(key string) string {
+	if dg.keyHashTag == "" {
+		return key
+	}
+
+	return fmt.Sprintf("{%s}:%s", dg.keyHashTag, key)
 }
 
 func (dg *dragonfly) SetUniqueKey(ctx context.Context, key string, value any, exp time.Duration) (bool, error) {
@@ -0,0 +1,418 @@
+package generator_storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// buffer keeps ids of one sys type, so a flood of requests of one sys type doesn't drain ids of the others.
+// It is refilled by blocks from the allocator of its counter namespace.
+type buffer struct {
+	sysType         string
+	namespace       string
+	allocator       Allocator
+	blockSize       int
+	idsCh           chan id
+	isFilling       chan struct{}
+	percentWhenFill float64
+	// closing is closed by close, refills stop after that.
+	closing chan struct{}
+
+	mu sync.Mutex
+	// refillErr is set while refills fail, unavailable is closed at the same time to wake up waiting callers.
+	refillErr   error
+	unavailable chan struct{}
+
+	issued         atomic.Int64
+	refills        atomic.Int64
+	refillFailures atomic.Int64
+	waits          atomic.Int64
+}
+
+// BufferStats describes buffer of one sys type.
+type BufferStats struct {
+	SysType          string `json:"sys_type"`
+	CounterNamespace string `json:"counter_namespace"`
+	// Buffered is number of ids ready to be issued, Capacity is the maximum of it.
+	Buffered int   `json:"buffered"`
+	Capacity int   `json:"capacity"`
+	Issued   int64 `json:"issued"`
+	// Refills is number of blocks got from the allocator, RefillFailures is number of failed attempts to get them.
+	Refills        int64 `json:"refills"`
+	RefillFailures int64 `json:"refill_failures"`
+	// Waits is number of requests, which found the buffer empty.
+	Waits       int64 `json:"waits"`
+	Unavailable bool  `json:"unavailable"`
+}
+
+func newBuffer(sysType, namespace string, allocator Allocator, blockSize, blocks int, percentWhenFill float64) *buffer {
+	return &buffer{
+		sysType:         sysType,
+		namespace:       namespace,
+		allocator:       allocator,
+		blockSize:       blockSize,
+		idsCh:           make(chan id, blockSize*blocks),
+		isFilling:       make(chan struct{}, 1),
+		percentWhenFill: percentWhenFill,
+		unavailable:     make(chan struct{}),
+		closing:         make(chan struct{}),
+	}
+}
+
+// getId returns buffered id. If there are no ids left, it waits for a refill until ctx is done
+// or returns UnavailableError while refills are failing.
+func (b *buffer) getId(ctx context.Context) (id, error) {
+	go b.fill()
+
+	select {
+	case rawId := <-b.idsCh:
+		b.issued.Add(1)
+		return rawId, nil
+	default:
+	}
+
+	b.waits.Add(1)
+
+	ctx, span := tracer.Start(ctx, "buffer.waitForRefill", trace.WithAttributes(attribute.String("sys_type", b.sysType)))
+	defer span.End()
+
+	for {
+		unavailable, err := b.state()
+		if err != nil {
+			return id{}, err
+		}
+
+		select {
+		case rawId := <-b.idsCh:
+			b.issued.Add(1)
+			return rawId, nil
+		case <-unavailable:
+		case <-ctx.Done():
+			return id{}, fmt.Errorf("waiting for id was interrupted: %w", ctx.Err())
+		}
+	}
+}
+
+// getIds returns n ids. Buffered ids are used first, the rest is taken from fresh
+// multiplier blocks, so only one round trip per block is made instead of one per id.
+func (b *buffer) getIds(ctx context.Context, n int) ([]id, error) {
+	rawIds := make([]id, 0, n)
+
+drain:
+	for len(rawIds) < n {
+		select {
+		case rawId := <-b.idsCh:
+			rawIds = append(rawIds, rawId)
+		default:
+			break drain
+		}
+	}
+
+	for len(rawIds) < n {
+		multiplier, timestamp, err := b.getMultiplierAndTimestamp(ctx)
+		if err != nil {
+			// ids taken so far aren't issued, so they are given to the next requests
+			b.putIds(rawIds)
+
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("waiting for ids was interrupted: %w", ctx.Err())
+			}
+
+			return nil, &UnavailableError{err}
+		}
+
+		newIds := b.generateIds(multiplier, timestamp)
+		idsLeft := n - len(rawIds)
+		if idsLeft >= len(newIds) {
+			rawIds = append(rawIds, newIds...)
+			continue
+		}
+
+		rawIds = append(rawIds, newIds[:idsLeft]...)
+		b.putIds(newIds[idsLeft:])
+	}
+
+	b.issued.Add(int64(n))
+
+	go b.fill()
+
+	return rawIds, nil
+}
+
+func (b *buffer) fill() {
+	if b.isFillNeeded() {
+		select {
+		case b.isFilling <- struct{}{}:
+		default:
+			return
+		}
+	} else {
+		return
+	}
+
+	defer func() {
+		<-b.isFilling
+	}()
+
+	ctx, span := tracer.Start(context.Background(), "buffer.fill", trace.WithAttributes(
+		attribute.String("sys_type", b.sysType), attribute.String("counter_namespace", b.namespace),
+	))
+	defer span.End()
+
+	for b.isFillNeeded() && !b.isClosing() {
+		var (
+			multiplier int32
+			timestamp  int64
+			err        error
+		)
+
+		for backoff := minRefillBackoff; ; backoff = min(backoff*2, maxRefillBackoff) {
+			multiplier, timestamp, err = b.getMultiplierAndTimestamp(ctx)
+			if err == nil {
+				break
+			}
+
+			b.refillFailures.Add(1)
+			b.setUnavailable(err)
+			log.Printf("could not get multiplier and timestamp for %s, next attempt in %v: %v", b.sysType, backoff, err)
+
+			select {
+			case <-time.After(backoff):
+			case <-b.closing:
+				return
+			}
+		}
+
+		b.setAvailable()
+		b.refills.Add(1)
+
+		for _, id := range b.generateIds(multiplier, timestamp) {
+			b.idsCh <- id
+		}
+	}
+}
+
+func (b *buffer) isClosing() bool {
+	select {
+	case <-b.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops refills and returns ids left in the buffer. The running refill is waited for, ids it puts
+// into the buffer meanwhile are taken out, so it isn't blocked by the full buffer. If ctx is done first,
+// ids taken out so far are returned with the error.
+func (b *buffer) close(ctx context.Context) ([]id, error) {
+	close(b.closing)
+
+	var err error
+	unusedIds := make([]id, 0, len(b.idsCh))
+
+waitForRefill:
+	for {
+		select {
+		case rawId := <-b.idsCh:
+			unusedIds = append(unusedIds, rawId)
+		case b.isFilling <- struct{}{}:
+			break waitForRefill
+		case <-ctx.Done():
+			err = fmt.Errorf("waiting for refill of %s was interrupted: %w", b.sysType, ctx.Err())
+			break waitForRefill
+		}
+	}
+
+	for {
+		select {
+		case rawId := <-b.idsCh:
+			unusedIds = append(unusedIds, rawId)
+		default:
+			return unusedIds, err
+		}
+	}
+}
+
+// unusedBlock is a range of unused ids of one block, ids are taken from the buffer in order, so the range has no gaps.
+type unusedBlock struct {
+	multiplier int32
+	timestamp  int64
+	minTail    int32
+	maxTail    int32
+	count      int
+}
+
+// groupIds groups ids by blocks they are generated from, in order of the first id of each block.
+func (b *buffer) groupIds(ids []id) []*unusedBlock {
+	var blocks []*unusedBlock
+	byKey := make(map[unusedBlock]*unusedBlock)
+
+	for _, rawId := range ids {
+		multiplier := rawId.Tail/int32(b.blockSize) + 1
+		key := unusedBlock{multiplier: multiplier, timestamp: rawId.Timestamp}
+
+		block, ok := byKey[key]
+		if !ok {
+			block = &unusedBlock{multiplier: multiplier, timestamp: rawId.Timestamp, minTail: rawId.Tail, maxTail: rawId.Tail}
+			byKey[key] = block
+			blocks = append(blocks, block)
+		}
+
+		block.minTail = min(block.minTail, rawId.Tail)
+		block.maxTail = max(block.maxTail, rawId.Tail)
+		block.count++
+	}
+
+	return blocks
+}
+
+// logUnusedBlock logs the range of ids of the block, which are never issued, so it can be audited.
+func (b *buffer) logUnusedBlock(block *unusedBlock) {
+	log.Printf(
+		"unused ids of %s: block %d of timestamp %d, tails %d-%d (%d ids)",
+		b.sysType, block.multiplier, block.timestamp, block.minTail, block.maxTail, block.count,
+	)
+}
+
+// releaseIds releases whole unused blocks to the allocator if it implements Releaser, ranges of partly
+// used blocks and blocks the allocator didn't take back are logged, so they can be audited.
+func (b *buffer) releaseIds(ctx context.Context, unusedIds []id) error {
+	blocks := b.groupIds(unusedIds)
+
+	releaser, canRelease := b.allocator.(Releaser)
+
+	released := 0
+	for _, block := range blocks {
+		if canRelease && block.count == b.blockSize && ctx.Err() == nil {
+			err := releaser.ReleaseBlock(ctx, block.multiplier, block.timestamp)
+			switch {
+			case err == nil:
+				released++
+				continue
+			case errors.Is(err, errors.ErrUnsupported):
+				// wrappers of allocators implement Releaser even if the wrapped allocator doesn't
+				canRelease = false
+			default:
+				log.Printf("could not release block %d of timestamp %d of %s: %v", block.multiplier, block.timestamp, b.sysType, err)
+			}
+		}
+
+		b.logUnusedBlock(block)
+	}
+
+	if released > 0 {
+		log.Printf("%d unused blocks of %s are released", released, b.sysType)
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("releasing blocks of %s was interrupted: %w", b.sysType, ctx.Err())
+	}
+
+	return nil
+}
+
+// returnIds puts issued ids, which the caller failed to use, back into the buffer.
+func (b *buffer) returnIds(ids []id) {
+	b.issued.Add(-int64(len(ids)))
+	b.putIds(ids)
+}
+
+// putIds returns leftover ids of a block to the channel. Ids that don't fit are dropped and logged.
+func (b *buffer) putIds(ids []id) {
+	for i, id := range ids {
+		select {
+		case b.idsCh <- id:
+		default:
+			for _, block := range b.groupIds(ids[i:]) {
+				b.logUnusedBlock(block)
+			}
+
+			return
+		}
+	}
+}
+
+// state returns channel, which is closed when buffer becomes unavailable, and the error if it already is.
+func (b *buffer) state() (<-chan struct{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.unavailable, b.refillErr
+}
+
+func (b *buffer) setUnavailable(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.refillErr == nil {
+		close(b.unavailable)
+	}
+
+	b.refillErr = &UnavailableError{err}
+}
+
+func (b *buffer) setAvailable() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.refillErr != nil {
+		b.refillErr = nil
+		b.unavailable = make(chan struct{})
+	}
+}
+
+func (b *buffer) isFillNeeded() bool {
+	idsLeftPercentage := float64(len(b.idsCh)) / float64(cap(b.idsCh))
+
+	return idsLeftPercentage < b.percentWhenFill
+}
+
+func (b *buffer) generateIds(multiplier int32, timestamp int64) []id {
+	newIds := make([]id, b.blockSize)
+	min := int(multiplier)*b.blockSize - b.blockSize
+
+	for i := range newIds {
+		newIds[i] = id{timestamp, int32(min + i)}
+	}
+
+	return newIds
+}
+
+func (b *buffer) getMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	ctx, span := tracer.Start(ctx, "buffer.getMultiplierAndTimestamp", trace.WithAttributes(
+		attribute.String("sys_type", b.sysType), attribute.String("counter_namespace", b.namespace),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("multiplier", int(multiplier)), attribute.Int64("timestamp", timestamp))
+		endSpan(span, err)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, allocationTimeout)
+	defer cancel()
+
+	return b.allocator.GetMultiplierAndTimestamp(ctx)
+}
+
+func (b *buffer) stats() BufferStats {
+	_, err := b.state()
+
+	return BufferStats{
+		SysType:          b.sysType,
+		CounterNamespace: b.namespace,
+		Buffered:         len(b.idsCh),
+		Capacity:         cap(b.idsCh),
+		Issued:           b.issued.Load(),
+		Refills:          b.refills.Load(),
+		RefillFailures:   b.refillFailures.Load(),
+		Waits:            b.waits.Load(),
+		Unavailable:      err != nil,
+	}
+}
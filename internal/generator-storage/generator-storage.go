@@ -2,186 +2,585 @@ package generator_storage
 
 import (
 	"context"
-	"crypto/sha1"
-	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
-	"math"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"id-generator/internal/cache"
-	"id-generator/internal/lib"
+	"id-generator/internal/tracing"
+	"id-generator/pkg/idformat"
 
-	_ "embed"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("id-generator/internal/generator-storage")
+
+const (
+	// MaxIdsPerRequest limits how many ids can be requested at once by GetUniqueIdsWithType.
+	MaxIdsPerRequest = 100000
+
+	// allocationTimeout limits getting of one block, including retries of the allocator.
+	allocationTimeout = 5 * time.Second
+
+	minRefillBackoff = 100 * time.Millisecond
+	maxRefillBackoff = 10 * time.Second
+)
+
+// ErrStorageClosed is returned for requests to the storage after Close.
+var ErrStorageClosed = errors.New("storage is closed")
+
+// UnavailableError is returned while storage has no buffered ids and can't get a new block from its allocator.
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("storage is unavailable: %v", e.Err)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidRequestError is returned for requests, which can't be served whatever state the storage is in:
+// unknown sys types, numbers of ids out of range and formats the namespace doesn't support.
+type InvalidRequestError struct {
+	Err error
+}
+
+func (e *InvalidRequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidRequestError) Unwrap() error {
+	return e.Err
+}
+
 type id struct {
 	Timestamp int64
 	Tail      int32
 }
 
-type Storage struct {
-	scriptSha            string
-	redisCounterKey      string
-	redisTimestampKey    string
-	maxAllowedMultiplier int
-	idsCh                chan id
-	// masterGrpcClient     pb.OrchestratorClient
-	isFilling       chan struct{}
-	percentWhenFill float64
+// Allocator gives out unique pairs of multiplier and timestamp, blocks of ids are generated from.
+type Allocator interface {
+	GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error)
 }
 
-//go:embed redis-script.lua
-var redisScript string
+// Releaser is implemented by allocators, which can give out unused blocks again within their timestamp.
+// Wrappers of allocators return errors.ErrUnsupported if the wrapped allocator can't do it.
+type Releaser interface {
+	ReleaseBlock(ctx context.Context, multiplier int32, timestamp int64) error
+}
 
-func NewStorage(
-	redisCounterKey, redisTimestampKey, maxAllowedMultiplierStr, freeDigitsForIdsStr string,
-	percentWhenFill float64,
-) (*Storage, error) {
-	if redisCounterKey == "" || redisTimestampKey == "" {
-		return nil, fmt.Errorf("redis keys REDIS_COUNTER_KEY or REDIS_TIMESTAMP_KEY must not be empty")
+// ReleaseTo releases the block to the allocator if it implements Releaser, otherwise it returns
+// errors.ErrUnsupported. Wrappers of allocators implement ReleaseBlock with it.
+func ReleaseTo(ctx context.Context, allocator Allocator, multiplier int32, timestamp int64) error {
+	releaser, ok := allocator.(Releaser)
+	if !ok {
+		return fmt.Errorf("%w: allocator doesn't take blocks back", errors.ErrUnsupported)
 	}
 
-	maxAllowedMultiplier, err := strconv.Atoi(maxAllowedMultiplierStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	return releaser.ReleaseBlock(ctx, multiplier, timestamp)
+}
+
+// AllocatorFactory returns allocator of the counter namespace, empty namespace is the default one.
+type AllocatorFactory func(namespace string) (Allocator, error)
+
+// SharedAllocator returns factory, which gives the same allocator to every counter namespace,
+// so sys types still get their own buffers, but share one counter.
+func SharedAllocator(allocator Allocator) AllocatorFactory {
+	return func(string) (Allocator, error) {
+		return allocator, nil
 	}
+}
 
-	freeDigitsForIds, err := strconv.Atoi(freeDigitsForIdsStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert to int FREE_DIGITS_FOR_IDS")
+type Storage struct {
+	layout idformat.Layout
+	// prefix of namespace is put before formatted ids, see Namespace.
+	prefix string
+	// buffers are keyed by name of sys type, bufferList keeps them in order of sys types for stats.
+	buffers    map[string]*buffer
+	bufferList []*buffer
+
+	// mu guards closed, requests are added to inFlight only while the storage isn't closed.
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+
+	// closeOnce runs shutdown once, calls of Close get its closeErr.
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewStorage creates buffer for every sys type of the layout. Every counter namespace gets one allocator
+// from the factory, buffers of sys types with the same namespace share it.
+func NewStorage(allocators AllocatorFactory, layout idformat.Layout, percentWhenFill float64) (*Storage, error) {
+	if allocators == nil {
+		return nil, fmt.Errorf("allocator must not be nil")
 	}
 
-	maxNumberOfIds := math.Pow10(freeDigitsForIds)
-	if maxNumberOfIds < float64(maxAllowedMultiplier) {
-		return nil, fmt.Errorf("10^(FREE_DIGITS_FOR_IDS) must not be less than MAX_ALLOWED_MULTIPLIER")
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid id layout: %v", err)
 	}
 
 	storage := &Storage{
-		"",
-		redisCounterKey,
-		redisTimestampKey,
-		maxAllowedMultiplier,
-		make(chan id, int(maxNumberOfIds/float64(maxAllowedMultiplier))),
-		make(chan struct{}, 1),
-		percentWhenFill,
+		layout:  layout,
+		buffers: make(map[string]*buffer),
+	}
+
+	namespaceAllocators := make(map[string]Allocator)
+	for _, sysType := range layout.GetSysTypes().List() {
+		allocator, ok := namespaceAllocators[sysType.CounterNamespace]
+		if !ok {
+			var err error
+			if allocator, err = allocators(sysType.CounterNamespace); err != nil {
+				return nil, fmt.Errorf("failed to create allocator of counter namespace %q: %v", sysType.CounterNamespace, err)
+			}
+			if allocator == nil {
+				return nil, fmt.Errorf("allocator of counter namespace %q must not be nil", sysType.CounterNamespace)
+			}
+
+			namespaceAllocators[sysType.CounterNamespace] = allocator
+		}
+
+		whenFill := percentWhenFill
+		if sysType.WhenFill > 0 {
+			whenFill = sysType.WhenFill
+		}
+
+		blocks := max(sysType.BufferBlocks, 1)
+
+		buffer := newBuffer(sysType.Name, sysType.CounterNamespace, allocator, layout.BlockSize(), blocks, whenFill)
+		storage.buffers[sysType.Name] = buffer
+		storage.bufferList = append(storage.bufferList, buffer)
 	}
 
-	storage.loadRedisScript()
-	storage.fill()
+	for _, buffer := range storage.bufferList {
+		go buffer.fill()
+	}
 
 	return storage, nil
 }
 
-// func (s *Storage) Init(masterGrpcClient pb.OrchestratorClient) {
-// 	s.masterGrpcClient = masterGrpcClient
-// 	s.fill()
-// }
+func (s *Storage) getBuffer(sysType string) (*buffer, error) {
+	buffer, ok := s.buffers[sysType]
+	if !ok {
+		return nil, &InvalidRequestError{fmt.Errorf("unknown sys_type: %s", sysType)}
+	}
+
+	return buffer, nil
+}
 
-func (s *Storage) GetRawId() id {
-	go s.fill()
+// startRequest adds request to in-flight ones, which Close waits for. done must be called when it is over.
+func (s *Storage) startRequest() (done func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return <-s.idsCh
+	if s.closed {
+		return nil, ErrStorageClosed
+	}
+
+	s.inFlight.Add(1)
+
+	return s.inFlight.Done, nil
 }
 
-func (s *Storage) GetUniqueIdWithType(sysType string) (newId string, err error) {
-	sysTypeId, err := lib.GetSysTypeValue(sysType)
+// Close rejects new requests with ErrStorageClosed, waits for in-flight ones and stops refills of buffers.
+// Ids left in buffers are reported back: whole unused blocks are released to allocators implementing Releaser,
+// so they are given out again within their timestamp, the rest of unused ids are logged. If ctx is done first,
+// buffers are closed anyway and all ids left in them are logged. Later calls return the error of the first one.
+func (s *Storage) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.closeErr = s.close(ctx)
+	})
+
+	return s.closeErr
+}
+
+func (s *Storage) close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	requestsDone := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(requestsDone)
+	}()
+
+	var errs []error
+
+	select {
+	case <-requestsDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("waiting for in-flight requests was interrupted: %w", ctx.Err()))
+	}
+
+	for _, buffer := range s.bufferList {
+		unusedIds, err := buffer.close(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := buffer.releaseIds(ctx, unusedIds); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetRawIdContext returns id from buffer of the sys type. If there are no ids left, it waits for a refill
+// until ctx is done or returns UnavailableError while refills are failing.
+func (s *Storage) GetRawIdContext(ctx context.Context, sysType string) (id, error) {
+	buffer, err := s.getBuffer(sysType)
 	if err != nil {
-		return "", err
+		return id{}, err
+	}
+
+	done, err := s.startRequest()
+	if err != nil {
+		return id{}, err
+	}
+	defer done()
+
+	return buffer.getId(ctx)
+}
+
+// GetRawIds returns n ids of the sys type. Buffered ids are used first, the rest is taken from fresh
+// multiplier blocks, so only one round trip per block is made instead of one per id.
+func (s *Storage) GetRawIds(ctx context.Context, sysType string, n int) ([]id, error) {
+	buffer, err := s.getBuffer(sysType)
+	if err != nil {
+		return nil, err
 	}
 
-	rawId := s.GetRawId()
+	done, err := s.startRequest()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 
-	return fmt.Sprintf("%010d%01d%07d", rawId.Timestamp, sysTypeId, rawId.Tail), nil
+	return buffer.getIds(ctx, n)
 }
 
-func (s *Storage) fill() {
-	if s.isFillNeeded() {
-		select {
-		case s.isFilling <- struct{}{}:
-		default:
-			return
+// Stats returns stats of buffers in order of sys types.
+func (s *Storage) Stats() []BufferStats {
+	stats := make([]BufferStats, len(s.bufferList))
+	for i, buffer := range s.bufferList {
+		stats[i] = buffer.stats()
+	}
+
+	return stats
+}
+
+// Health returns error while the storage is closed, refills of a buffer fail, because the allocator is unreachable,
+// or the storage has no ids at all. A buffer emptied by requests between refills isn't an error, busy sys types
+// drain their buffers under normal load.
+func (s *Storage) Health() error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+
+	if closed {
+		return ErrStorageClosed
+	}
+
+	buffered := 0
+	for _, buffer := range s.bufferList {
+		if _, err := buffer.state(); err != nil {
+			return fmt.Errorf("buffer of %s: %w", buffer.sysType, err)
 		}
-	} else {
-		return
+
+		buffered += len(buffer.idsCh)
 	}
 
-	defer func() {
-		<-s.isFilling
-	}()
+	if buffered == 0 {
+		return fmt.Errorf("storage has no ids")
+	}
+
+	return nil
+}
+
+// Format is a format of string ids.
+type Format int
+
+const (
+	// FormatDecimal is the decimal format of the layout, ids can be encoded with IdOptions.Encoding.
+	FormatDecimal Format = iota
+	// FormatUUIDv7 is UUIDv7 of RFC 9562, see idformat.Layout.UUIDv7.
+	FormatUUIDv7
+	// FormatULID is ULID, see idformat.Layout.ULID.
+	FormatULID
+)
+
+// IdOptions are options of ids of one request.
+type IdOptions struct {
+	// ShardKey routes ids to the digit of the key, see idformat.SysTypes.ValueForKey, otherwise the digit is random.
+	ShardKey string
+	// Encoding encodes decimal ids if it is set. Int64 ids can't be encoded.
+	Encoding *idformat.Encoding
+	// Format of string ids, UUIDs and ULIDs can't be encoded and carry prefix of namespace.
+	Format Format
+}
+
+// typedId is the raw id with value of its sys type.
+type typedId struct {
+	id
+	SysTypeId int8
+}
 
-	// ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	// defer cancel()
+func (s *Storage) getTypedId(ctx context.Context, sysType string, opts IdOptions) (typedId, error) {
+	sysTypeId, err := s.layout.GetSysTypes().ValueForKey(sysType, opts.ShardKey)
+	if err != nil {
+		return typedId{}, &InvalidRequestError{err}
+	}
 
-	multiplier, timestamp, err := s.getMultiplierAndTimestamp()
-	// fmt.Println(multiplier)
-	// fmt.Println(timestamp)
+	rawId, err := s.GetRawIdContext(ctx, sysType)
 	if err != nil {
-		log.Fatalf("could not get data from master server: %v", err)
+		return typedId{}, err
 	}
 
-	newIds := s.generateIdsByChanCapacity(multiplier, timestamp)
+	return typedId{rawId, sysTypeId}, nil
+}
+
+func (s *Storage) GetUniqueIdWithType(ctx context.Context, sysType string, opts IdOptions) (newId string, err error) {
+	ctx, span := startSpan(ctx, "Storage.GetUniqueIdWithType", sysType, 1)
+	defer func() { endSpan(span, err) }()
+
+	if err := s.checkFormat(opts); err != nil {
+		return "", err
+	}
 
-	for _, id := range newIds {
-		s.idsCh <- id
+	newTypedId, err := s.getTypedId(ctx, sysType, opts)
+	if err != nil {
+		return "", err
 	}
+
+	if newId, err = s.formatId(newTypedId, opts); err != nil {
+		s.returnIds(sysType, []typedId{newTypedId})
+		return "", err
+	}
+
+	return newId, nil
 }
 
-func (s *Storage) isFillNeeded() bool {
-	idsLeftPercentage := float64(len(s.idsCh)) / float64(cap(s.idsCh))
+// GetUniqueNumericIdWithType returns id packed into int64, see idformat.Layout.Pack.
+func (s *Storage) GetUniqueNumericIdWithType(ctx context.Context, sysType string, opts IdOptions) (newId int64, err error) {
+	ctx, span := startSpan(ctx, "Storage.GetUniqueNumericIdWithType", sysType, 1)
+	defer func() { endSpan(span, err) }()
+
+	if err := s.checkNumeric(opts); err != nil {
+		return 0, err
+	}
 
-	return idsLeftPercentage < s.percentWhenFill
+	newTypedId, err := s.getTypedId(ctx, sysType, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if newId, err = s.layout.Pack(newTypedId.Timestamp, newTypedId.SysTypeId, newTypedId.Tail); err != nil {
+		s.returnIds(sysType, []typedId{newTypedId})
+		return 0, err
+	}
+
+	return newId, nil
 }
 
-func (s *Storage) generateIdsByChanCapacity(multiplier int32, timestamp int64) []id {
-	chanCap := cap(s.idsCh)
-	newIds := make([]id, chanCap)
-	min := int(multiplier)*chanCap - chanCap
+func (s *Storage) getTypedIds(ctx context.Context, sysType string, opts IdOptions, n int) ([]typedId, error) {
+	if n < 1 || n > MaxIdsPerRequest {
+		return nil, &InvalidRequestError{fmt.Errorf("number of ids must be between 1 and %d, got %d", MaxIdsPerRequest, n)}
+	}
 
-	for i := range newIds {
-		newIds[i] = id{timestamp, int32(min + i)}
+	sysTypeId, err := s.layout.GetSysTypes().ValueForKey(sysType, opts.ShardKey)
+	if err != nil {
+		return nil, &InvalidRequestError{err}
 	}
 
-	return newIds
+	rawIds, err := s.GetRawIds(ctx, sysType, n)
+	if err != nil {
+		return nil, err
+	}
+
+	typedIds := make([]typedId, len(rawIds))
+	for i, rawId := range rawIds {
+		typedIds[i] = typedId{rawId, sysTypeId}
+	}
+
+	return typedIds, nil
+}
+
+// returnIds puts ids, which failed to be formatted, back into buffer of the sys type, so they aren't lost.
+func (s *Storage) returnIds(sysType string, typedIds []typedId) {
+	buffer, err := s.getBuffer(sysType)
+	if err != nil {
+		return
+	}
+
+	rawIds := make([]id, len(typedIds))
+	for i, typedId := range typedIds {
+		rawIds[i] = typedId.id
+	}
+
+	buffer.returnIds(rawIds)
 }
 
-func (s *Storage) getMultiplierAndTimestamp() (multiplier int32, timestamp int64, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+func (s *Storage) GetUniqueIdsWithType(ctx context.Context, sysType string, opts IdOptions, n int) (newIds []string, err error) {
+	ctx, span := startSpan(ctx, "Storage.GetUniqueIdsWithType", sysType, n)
+	defer func() { endSpan(span, err) }()
+
+	if err := s.checkFormat(opts); err != nil {
+		return nil, err
+	}
 
-	result, err := cache.Dragonfly.RawClient.EvalSha(
-		ctx,
-		s.scriptSha, []string{s.redisCounterKey, s.redisTimestampKey}, s.maxAllowedMultiplier,
-	).Int64Slice()
+	typedIds, err := s.getTypedIds(ctx, sysType, opts, n)
 	if err != nil {
-		return 0, 0, fmt.Errorf("there was an error while getting multiplier or timestamp: %v", err)
+		return nil, err
 	}
 
-	return int32(result[0]), result[1], nil
+	newIds = make([]string, len(typedIds))
+	for i, newTypedId := range typedIds {
+		if newIds[i], err = s.formatId(newTypedId, opts); err != nil {
+			s.returnIds(sysType, typedIds)
+			return nil, err
+		}
+	}
+
+	return newIds, nil
 }
 
-func (s *Storage) loadRedisScript() {
-	hash := sha1.New()
-	hash.Write([]byte(redisScript))
-	scriptSha := hex.EncodeToString(hash.Sum(nil))
+func (s *Storage) GetUniqueNumericIdsWithType(ctx context.Context, sysType string, opts IdOptions, n int) (newIds []int64, err error) {
+	ctx, span := startSpan(ctx, "Storage.GetUniqueNumericIdsWithType", sysType, n)
+	defer func() { endSpan(span, err) }()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	if err := s.checkNumeric(opts); err != nil {
+		return nil, err
+	}
 
-	results, err := cache.Dragonfly.RawClient.ScriptExists(ctx, scriptSha).Result()
+	typedIds, err := s.getTypedIds(ctx, sysType, opts, n)
 	if err != nil {
-		fmt.Printf("failed to check if script exists by sha representation: %v\n", err)
+		return nil, err
 	}
 
-	s.scriptSha = scriptSha
+	newIds = make([]int64, len(typedIds))
+	for i, newTypedId := range typedIds {
+		if newIds[i], err = s.layout.Pack(newTypedId.Timestamp, newTypedId.SysTypeId, newTypedId.Tail); err != nil {
+			s.returnIds(sysType, typedIds)
+			return nil, err
+		}
+	}
 
-	if results[0] {
-		return
+	return newIds, nil
+}
+
+// startSpan starts span of request of n ids of the sys type.
+func startSpan(ctx context.Context, name, sysType string, n int) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.String("sys_type", sysType), attribute.Int("count", n)))
+}
+
+func endSpan(span trace.Span, err error) {
+	tracing.RecordError(span, err)
+	span.End()
+}
+
+// SysTypes returns registry of sys types ids are issued for.
+func (s *Storage) SysTypes() *idformat.SysTypes {
+	return s.layout.GetSysTypes()
+}
+
+// DecodeId decodes id issued by storage with the same configuration in the format and encoding of options.
+func (s *Storage) DecodeId(id string, opts IdOptions) (idformat.ID, error) {
+	if s.prefix != "" {
+		body, ok := strings.CutPrefix(id, s.prefix+PrefixSeparator)
+		if !ok {
+			return idformat.ID{}, fmt.Errorf("id must start with prefix of namespace %s%s", s.prefix, PrefixSeparator)
+		}
+
+		id = body
+	}
+
+	return decodeId(s.layout, id, opts)
+}
+
+func (s *Storage) formatId(newTypedId typedId, opts IdOptions) (string, error) {
+	switch opts.Format {
+	case FormatUUIDv7:
+		return s.layout.UUIDv7(newTypedId.Timestamp, newTypedId.SysTypeId, newTypedId.Tail)
+	case FormatULID:
+		return s.layout.ULID(newTypedId.Timestamp, newTypedId.SysTypeId, newTypedId.Tail)
 	}
 
-	_, err = cache.Dragonfly.RawClient.ScriptLoad(ctx, redisScript).Result()
+	newId, err := s.layout.Format(newTypedId.Timestamp, newTypedId.SysTypeId, newTypedId.Tail)
 	if err != nil {
-		log.Fatalf("failed to load script: %v", err)
+		return "", err
+	}
+
+	if opts.Encoding != nil {
+		if newId, err = opts.Encoding.Encode(newId); err != nil {
+			return "", err
+		}
+	}
+
+	if s.prefix != "" {
+		return s.prefix + PrefixSeparator + newId, nil
+	}
+
+	return newId, nil
+}
+
+// checkNumeric returns error if ids of the storage can't be packed into int64, because they need a prefix or encoding,
+// or timestamps of the layout overflow int64.
+func (s *Storage) checkNumeric(opts IdOptions) error {
+	if s.prefix != "" {
+		return &InvalidRequestError{fmt.Errorf("int64 ids can't carry prefix %s of namespace, use decimal format", s.prefix)}
+	}
+
+	if opts.Encoding != nil {
+		return &InvalidRequestError{fmt.Errorf("int64 ids can't be encoded with %s", opts.Encoding.Name())}
+	}
+
+	if err := s.layout.ValidatePack(); err != nil {
+		return &InvalidRequestError{err}
+	}
+
+	return nil
+}
+
+// checkFormat returns error if string ids of the storage can't be in the format, because they need a prefix or encoding.
+func (s *Storage) checkFormat(opts IdOptions) error {
+	if opts.Format == FormatDecimal {
+		return nil
+	}
+
+	if s.prefix != "" {
+		return &InvalidRequestError{fmt.Errorf("uuid and ulid ids can't carry prefix %s of namespace, use decimal format", s.prefix)}
+	}
+
+	if opts.Encoding != nil {
+		return &InvalidRequestError{fmt.Errorf("uuid and ulid ids can't be encoded with %s", opts.Encoding.Name())}
 	}
+
+	return nil
+}
+
+// decodeId decodes id without prefix of namespace in the format, encoded ids are decoded to decimal ones first.
+func decodeId(layout idformat.Layout, id string, opts IdOptions) (idformat.ID, error) {
+	switch opts.Format {
+	case FormatUUIDv7:
+		return layout.ParseUUIDv7(id)
+	case FormatULID:
+		return layout.ParseULID(id)
+	}
+
+	if opts.Encoding != nil {
+		var err error
+		if id, err = opts.Encoding.Decode(id, layout.Length()); err != nil {
+			return idformat.ID{}, err
+		}
+	}
+
+	return layout.Parse(id)
 }
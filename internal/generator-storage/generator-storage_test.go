@@ -1,11 +1,17 @@
 package generator_storage
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"id-generator/internal/allocator"
 	"id-generator/internal/pb"
+	"id-generator/pkg/idformat"
 	"log"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -40,10 +46,24 @@ func getMasterGrpcClientFromEnv(envFile string) pb.OrchestratorClient {
 }
 
 func setup() {
-	testStorage_master1, _ = NewStorage("test-counter-key", "test-timestamp-key", "10000", "7", 0.3)
-	// testStorage_master1.Init(getMasterGrpcClientFromEnv("../../.env.master1"))
-	testStorage_master2, _ = NewStorage("test-counter-key", "test-timestamp-key", "10000", "7", 0.3)
-	// testStorage_master2.Init(getMasterGrpcClientFromEnv("../../.env.master2"))
+	redisAllocator, err := allocator.NewRedis("test-counter-key", "test-timestamp-key", "10000", idformat.DefaultClock)
+	if err != nil {
+		log.Fatalf("failed to create redis allocator: %v", err)
+	}
+
+	testStorage_master1, _ = NewStorage(SharedAllocator(redisAllocator), idformat.DefaultLayout, 0.3)
+	testStorage_master2, _ = NewStorage(SharedAllocator(redisAllocator), idformat.DefaultLayout, 0.3)
+
+	if os.Getenv("TEST_WITH_MASTERS") != "" {
+		testStorage_master1, _ = NewStorage(SharedAllocator(allocator.NewMaster(
+			getMasterGrpcClientFromEnv("../../.env.master1"), time.Second, 3, idformat.DefaultClock,
+			idformat.DefaultLayout.MaxAllowedMultiplier,
+		)), idformat.DefaultLayout, 0.3)
+		testStorage_master2, _ = NewStorage(SharedAllocator(allocator.NewMaster(
+			getMasterGrpcClientFromEnv("../../.env.master2"), time.Second, 3, idformat.DefaultClock,
+			idformat.DefaultLayout.MaxAllowedMultiplier,
+		)), idformat.DefaultLayout, 0.3)
+	}
 }
 
 func TestMain(m *testing.M) {
@@ -63,7 +83,7 @@ func TestIdsOnUniqueness(t *testing.T) {
 
 	go func() {
 		for range 1000000 {
-			id, err := testStorage_master1.GetUniqueIdWithType("Vendor")
+			id, err := testStorage_master1.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{})
 			if err != nil {
 				fmt.Println(err)
 				continue
@@ -79,7 +99,7 @@ func TestIdsOnUniqueness(t *testing.T) {
 
 	go func() {
 		for range 1000000 {
-			id, err := testStorage_master2.GetUniqueIdWithType("Vendor")
+			id, err := testStorage_master2.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{})
 			if err != nil {
 				fmt.Println(err)
 				continue
@@ -111,3 +131,575 @@ func TestIdsOnUniqueness(t *testing.T) {
 		t.Errorf("there are not unique ids: %q\n", notUniqueIds)
 	}
 }
+
+func TestBatchIdsOnUniqueness(t *testing.T) {
+	ids := make(map[string]struct{})
+
+	for _, count := range []int{1, 500, 2500, 25000} {
+		newIds, err := testStorage_master1.GetUniqueIdsWithType(context.Background(), "Box", IdOptions{}, count)
+		if err != nil {
+			t.Fatalf("failed to get batch of %d ids: %v", count, err)
+		}
+
+		if len(newIds) != count {
+			t.Errorf("expected %d ids, got %d", count, len(newIds))
+		}
+
+		for _, id := range newIds {
+			if _, ok := ids[id]; ok {
+				t.Errorf("there is not unique id: %s", id)
+			}
+			ids[id] = struct{}{}
+		}
+	}
+
+	if _, err := testStorage_master1.GetUniqueIdsWithType(context.Background(), "Vendor", IdOptions{}, MaxIdsPerRequest+1); err == nil {
+		t.Errorf("expected error when requesting more than %d ids", MaxIdsPerRequest)
+	}
+}
+
+type flakyAllocator struct {
+	*allocator.Local
+	isDown atomic.Bool
+}
+
+func (a *flakyAllocator) GetMultiplierAndTimestamp(ctx context.Context) (int32, int64, error) {
+	if a.isDown.Load() {
+		return 0, 0, fmt.Errorf("allocator is down")
+	}
+
+	return a.Local.GetMultiplierAndTimestamp(ctx)
+}
+
+func TestUnavailableUntilRefillSucceeds(t *testing.T) {
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+	flaky := &flakyAllocator{Local: localAllocator}
+	flaky.isDown.Store(true)
+
+	storage, err := NewStorage(SharedAllocator(flaky), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	var unavailableErr *UnavailableError
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); !errors.As(err, &unavailableErr) {
+		t.Fatalf("expected UnavailableError, got: %v", err)
+	}
+
+	flaky.isDown.Store(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{})
+		if err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("storage didn't recover after allocator is up: %v", err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+	flaky := &flakyAllocator{Local: localAllocator}
+	flaky.isDown.Store(true)
+
+	storage, err := NewStorage(SharedAllocator(flaky), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	if err := storage.Health(); err == nil {
+		t.Fatalf("storage without buffered ids is healthy")
+	}
+
+	flaky.isDown.Store(false)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for storage.Health() != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("storage didn't become healthy after allocator is up: %v", storage.Health())
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// a busy sys type drains its buffer between refills, the storage still has ids of others
+	drainBuffer(storage.buffers["Vendor"])
+
+	if err := storage.Health(); err != nil {
+		t.Errorf("storage with one drained buffer is unhealthy: %v", err)
+	}
+
+	for _, buffer := range storage.bufferList {
+		drainBuffer(buffer)
+	}
+
+	if err := storage.Health(); err == nil {
+		t.Errorf("storage without ids is healthy")
+	}
+
+	if err := storage.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close storage: %v", err)
+	}
+
+	if err := storage.Health(); !errors.Is(err, ErrStorageClosed) {
+		t.Errorf("expected ErrStorageClosed, got: %v", err)
+	}
+}
+
+// ctxAllocator fails when ctx is done, like allocators of Redis and master server do.
+type ctxAllocator struct {
+	*allocator.Local
+}
+
+func (a ctxAllocator) GetMultiplierAndTimestamp(ctx context.Context) (int32, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	return a.Local.GetMultiplierAndTimestamp(ctx)
+}
+
+func TestInterruptedBatchKeepsBufferedIds(t *testing.T) {
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+
+	storage, err := NewStorage(SharedAllocator(ctxAllocator{localAllocator}), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); err != nil {
+		t.Fatalf("failed to get id: %v", err)
+	}
+
+	// the refill started by the request is finished
+	time.Sleep(100 * time.Millisecond)
+	buffered := bufferStats(storage, "Vendor").Buffered
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := storage.GetUniqueIdsWithType(ctx, "Vendor", IdOptions{}, buffered+1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got: %v", err)
+	}
+
+	if stats := bufferStats(storage, "Vendor"); stats.Buffered != buffered || stats.Issued != 1 {
+		t.Errorf("expected %d buffered ids and 1 issued id, got %d and %d", buffered, stats.Buffered, stats.Issued)
+	}
+}
+
+func bufferStats(storage *Storage, sysType string) BufferStats {
+	for _, stats := range storage.Stats() {
+		if stats.SysType == sysType {
+			return stats
+		}
+	}
+
+	return BufferStats{}
+}
+
+func TestFailedFormattingKeepsIds(t *testing.T) {
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+
+	storage, err := NewStorage(SharedAllocator(localAllocator), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); err != nil {
+		t.Fatalf("failed to get id: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	buffered := bufferStats(storage, "Vendor").Buffered
+
+	// timestamps of seconds don't fit int64 ids with the wider tail
+	storage.layout.TailDigits = 9
+
+	if _, err := storage.GetUniqueNumericIdsWithType(context.Background(), "Vendor", IdOptions{}, 10); err == nil {
+		t.Fatalf("expected error of packing into int64")
+	}
+
+	if stats := bufferStats(storage, "Vendor"); stats.Buffered != buffered || stats.Issued != 1 {
+		t.Errorf("expected %d buffered ids and 1 issued id, got %d and %d", buffered, stats.Buffered, stats.Issued)
+	}
+}
+
+// drainBuffer takes all ids out of the buffer without starting a refill.
+func drainBuffer(b *buffer) {
+	for {
+		select {
+		case <-b.idsCh:
+		default:
+			return
+		}
+	}
+}
+
+type stalledAllocator struct{}
+
+func (stalledAllocator) GetMultiplierAndTimestamp(ctx context.Context) (int32, int64, error) {
+	<-ctx.Done()
+	return 0, 0, ctx.Err()
+}
+
+func TestGetIdRespectsDeadline(t *testing.T) {
+	storage, err := NewStorage(SharedAllocator(stalledAllocator{}), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = storage.GetUniqueIdWithType(ctx, "Vendor", IdOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request wasn't interrupted by deadline, took %v", elapsed)
+	}
+}
+
+func TestSysTypesHaveIsolatedBuffers(t *testing.T) {
+	sysTypes, err := idformat.NewSysTypes([]idformat.SysType{
+		{Name: "Vendor", MinDigit: 0, MaxDigit: 0, BufferBlocks: 3, WhenFill: 0.5},
+		{Name: "Clients", MinDigit: 9, MaxDigit: 9, CounterNamespace: "clients"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sys types: %v", err)
+	}
+
+	layout := idformat.DefaultLayout
+	layout.SysTypes = sysTypes
+
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+	storage, err := NewStorage(func(namespace string) (Allocator, error) {
+		if namespace == "clients" {
+			return stalledAllocator{}, nil
+		}
+
+		return localAllocator, nil
+	}, layout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := storage.GetUniqueIdWithType(ctx, "Clients", IdOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error for stalled Clients, got: %v", err)
+	}
+
+	for range 10 {
+		if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); err != nil {
+			t.Fatalf("Vendor ids must not depend on Clients counter: %v", err)
+		}
+	}
+
+	stats := storage.Stats()
+	if len(stats) != 2 || stats[0].SysType != "Vendor" || stats[1].SysType != "Clients" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	if stats[0].Capacity != 3*layout.BlockSize() || stats[0].Issued != 10 {
+		t.Errorf("unexpected stats of Vendor: %+v", stats[0])
+	}
+
+	if stats[1].Capacity != layout.BlockSize() || stats[1].CounterNamespace != "clients" || stats[1].Issued != 0 || stats[1].Waits != 1 {
+		t.Errorf("unexpected stats of Clients: %+v", stats[1])
+	}
+}
+
+func TestNamespacesAreIsolated(t *testing.T) {
+	localAllocators := func(string) (Allocator, error) {
+		return allocator.NewLocal("10000", idformat.DefaultClock)
+	}
+
+	shopLayout, err := idformat.ParseLayout("", "", "6", "100", idformat.DefaultClock, nil)
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+
+	registry, err := NewRegistry([]Namespace{
+		{Layout: idformat.DefaultLayout, Allocators: localAllocators, PercentWhenFill: 0.3},
+		{Name: "shop", Prefix: "SHOP", Layout: shopLayout, Allocators: func(string) (Allocator, error) {
+			return allocator.NewLocal("100", idformat.DefaultClock)
+		}, PercentWhenFill: 0.3},
+	})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	if len(registry.storages) != 0 {
+		t.Fatalf("storages must be created on the first request, got %d", len(registry.storages))
+	}
+
+	shopStorage, err := registry.Storage("shop")
+	if err != nil {
+		t.Fatalf("failed to get storage of namespace: %v", err)
+	}
+
+	shopId, err := shopStorage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{})
+	if err != nil {
+		t.Fatalf("failed to get id of namespace: %v", err)
+	}
+
+	if !strings.HasPrefix(shopId, "SHOP_") || len(shopId) != len("SHOP_")+shopLayout.Length() {
+		t.Errorf("unexpected id of namespace: %s", shopId)
+	}
+
+	namespace, decodedId, err := registry.DecodeId(shopId, IdOptions{})
+	if err != nil || namespace != "shop" || decodedId.SysType != "Vendor" {
+		t.Errorf("unexpected decoded id of namespace: %q %+v %v", namespace, decodedId, err)
+	}
+
+	block, _, err := registry.BlockOfId(shopId, IdOptions{})
+	if err != nil || block.Namespace != "shop" || block.Multiplier != decodedId.Multiplier || block.Timestamp != decodedId.Timestamp {
+		t.Errorf("unexpected block of id of namespace: %+v %v", block, err)
+	}
+
+	if _, _, err := registry.DecodeId(strings.TrimPrefix(shopId, "SHOP_"), IdOptions{}); err == nil {
+		t.Errorf("id of namespace without prefix must not be decoded by the default namespace")
+	}
+
+	base58, _ := idformat.ParseEncoding("base58")
+	encodedId, err := shopStorage.GetUniqueIdWithType(context.Background(), "Box", IdOptions{Encoding: base58})
+	if err != nil || !strings.HasPrefix(encodedId, "SHOP_") {
+		t.Fatalf("unexpected encoded id of namespace: %q %v", encodedId, err)
+	}
+
+	if namespace, decodedId, err := registry.DecodeId(encodedId, IdOptions{Encoding: base58}); err != nil || namespace != "shop" || decodedId.SysType != "Box" {
+		t.Errorf("unexpected decoded encoded id of namespace: %q %+v %v", namespace, decodedId, err)
+	}
+
+	if _, err := shopStorage.GetUniqueNumericIdWithType(context.Background(), "Vendor", IdOptions{}); err == nil {
+		t.Errorf("expected error for int64 id of namespace with prefix")
+	}
+
+	if _, err := shopStorage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{Format: FormatUUIDv7}); err == nil {
+		t.Errorf("expected error for uuid of namespace with prefix")
+	}
+
+	if len(registry.storages) != 1 {
+		t.Errorf("only storage of requested namespace must be created, got %d", len(registry.storages))
+	}
+
+	if _, err := registry.Storage("unknown"); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("expected unknown namespace error, got: %v", err)
+	}
+
+	if _, err := NewRegistry([]Namespace{
+		{Name: "shop", Prefix: "S", Layout: idformat.DefaultLayout, Allocators: localAllocators},
+		{Name: "blog", Prefix: "S", Layout: idformat.DefaultLayout, Allocators: localAllocators},
+	}); err == nil {
+		t.Errorf("expected error for namespaces with the same prefix")
+	}
+
+	if _, err := NewRegistry([]Namespace{
+		{Name: "shop", Layout: idformat.DefaultLayout, Allocators: localAllocators},
+	}); err == nil {
+		t.Errorf("expected error for named namespace without prefix")
+	}
+}
+
+func TestUUIDAndULIDIds(t *testing.T) {
+	registry, err := NewRegistry([]Namespace{{
+		Layout: idformat.DefaultLayout,
+		Allocators: func(string) (Allocator, error) {
+			return allocator.NewLocal("10000", idformat.DefaultClock)
+		},
+		PercentWhenFill: 0.3,
+	}})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	storage, err := registry.Storage("")
+	if err != nil {
+		t.Fatalf("failed to get storage: %v", err)
+	}
+
+	for _, format := range []Format{FormatUUIDv7, FormatULID} {
+		newIds, err := storage.GetUniqueIdsWithType(context.Background(), "Box", IdOptions{Format: format}, 100)
+		if err != nil {
+			t.Fatalf("failed to get ids of format %d: %v", format, err)
+		}
+
+		for i, newId := range newIds {
+			if i > 0 && newId <= newIds[i-1] {
+				t.Errorf("ids of format %d don't sort in order of issue: %s <= %s", format, newId, newIds[i-1])
+			}
+
+			if _, decodedId, err := registry.DecodeId(newId, IdOptions{Format: format}); err != nil || decodedId.SysType != "Box" {
+				t.Errorf("unexpected decoded id %s: %+v %v", newId, decodedId, err)
+			}
+		}
+	}
+
+	base32, _ := idformat.ParseEncoding("base32")
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Box", IdOptions{Format: FormatULID, Encoding: base32}); err == nil {
+		t.Errorf("expected error for encoded ulid")
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	local, err := allocator.NewLocal("10000", idformat.DefaultClock)
+	if err != nil {
+		t.Fatalf("failed to create local allocator: %v", err)
+	}
+
+	storage, err := NewStorage(SharedAllocator(local), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer storage.Close(context.Background())
+
+	base32, _ := idformat.ParseEncoding("base32")
+
+	for name, get := range map[string]func() error{
+		"unknown sys type": func() error {
+			_, err := storage.GetUniqueIdWithType(context.Background(), "Unknown", IdOptions{})
+			return err
+		},
+		"unknown sys type of raw id": func() error {
+			_, err := storage.GetRawIdContext(context.Background(), "Unknown")
+			return err
+		},
+		"no ids": func() error {
+			_, err := storage.GetUniqueIdsWithType(context.Background(), "Vendor", IdOptions{}, 0)
+			return err
+		},
+		"too many ids": func() error {
+			_, err := storage.GetUniqueNumericIdsWithType(context.Background(), "Vendor", IdOptions{}, MaxIdsPerRequest+1)
+			return err
+		},
+		"encoded int64 id": func() error {
+			_, err := storage.GetUniqueNumericIdWithType(context.Background(), "Vendor", IdOptions{Encoding: base32})
+			return err
+		},
+		"encoded uuid": func() error {
+			_, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{Format: FormatUUIDv7, Encoding: base32})
+			return err
+		},
+	} {
+		var invalidRequestErr *InvalidRequestError
+		if err := get(); !errors.As(err, &invalidRequestErr) {
+			t.Errorf("%s: expected invalid request error, got: %v", name, err)
+		}
+	}
+}
+
+// releasingAllocator records blocks released to it.
+type releasingAllocator struct {
+	*allocator.Local
+
+	mu       sync.Mutex
+	released []int32
+}
+
+func (a *releasingAllocator) ReleaseBlock(ctx context.Context, multiplier int32, timestamp int64) error {
+	a.mu.Lock()
+	a.released = append(a.released, multiplier)
+	a.mu.Unlock()
+
+	return a.Local.ReleaseBlock(ctx, multiplier, timestamp)
+}
+
+func TestCloseReleasesUnusedBlocks(t *testing.T) {
+	sysTypes, err := idformat.NewSysTypes([]idformat.SysType{
+		{Name: "Vendor", MinDigit: 0, MaxDigit: 0, BufferBlocks: 3, WhenFill: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sys types: %v", err)
+	}
+
+	layout := idformat.DefaultLayout
+	layout.SysTypes = sysTypes
+
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+	releasing := &releasingAllocator{Local: localAllocator}
+
+	storage, err := NewStorage(SharedAllocator(releasing), layout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	rawId, err := storage.GetRawIdContext(context.Background(), "Vendor")
+	if err != nil {
+		t.Fatalf("failed to get id: %v", err)
+	}
+	usedMultiplier := rawId.Tail/int32(layout.BlockSize()) + 1
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := storage.Close(ctx); err != nil {
+		t.Fatalf("failed to close storage: %v", err)
+	}
+
+	releasing.mu.Lock()
+	released := releasing.released
+	releasing.mu.Unlock()
+
+	if len(released) == 0 {
+		t.Fatalf("expected unused blocks to be released")
+	}
+
+	for _, multiplier := range released {
+		if multiplier == usedMultiplier {
+			t.Errorf("partly used block %d is released", multiplier)
+		}
+	}
+
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); !errors.Is(err, ErrStorageClosed) {
+		t.Errorf("expected closed storage error, got: %v", err)
+	}
+
+	if err := storage.Close(ctx); err != nil {
+		t.Errorf("second close must be a no-op, got: %v", err)
+	}
+}
+
+func TestCloseTimeoutStillClosesBuffers(t *testing.T) {
+	localAllocator, _ := allocator.NewLocal("10000", idformat.DefaultClock)
+
+	storage, err := NewStorage(SharedAllocator(localAllocator), idformat.DefaultLayout, 0.3)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+
+	if _, err := storage.GetUniqueIdWithType(context.Background(), "Vendor", IdOptions{}); err != nil {
+		t.Fatalf("failed to get id: %v", err)
+	}
+
+	// the request is never finished, so Close times out waiting for it
+	if _, err := storage.startRequest(); err != nil {
+		t.Fatalf("failed to start request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := storage.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, got: %v", err)
+	}
+
+	for _, buffer := range storage.bufferList {
+		if !buffer.isClosing() || len(buffer.idsCh) != 0 {
+			t.Errorf("buffer of %s isn't closed, %d ids are left in it", buffer.sysType, len(buffer.idsCh))
+		}
+	}
+
+	if err := storage.Close(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second close must return error of the first one, got: %v", err)
+	}
+}
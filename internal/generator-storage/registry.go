@@ -0,0 +1,229 @@
+package generator_storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"id-generator/pkg/idformat"
+)
+
+// PrefixSeparator separates prefix of namespace from the id. Decimal ids and all encodings of them
+// consist of letters and digits only, so the separator can't be confused with a part of an id.
+const PrefixSeparator = "_"
+
+// ErrUnknownNamespace is returned for requests of namespaces, which aren't configured.
+var ErrUnknownNamespace = errors.New("unknown namespace")
+
+var (
+	namespaceNamePattern   = regexp.MustCompile(`^[a-z0-9-]+$`)
+	namespacePrefixPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+)
+
+// Namespace is a tenant with its own counters, id layout and sys types. Ids of named namespaces are
+// prefixed with Prefix and PrefixSeparator, so they are never confused with ids of other namespaces.
+// The default namespace has empty name and no prefix.
+type Namespace struct {
+	Name            string
+	Prefix          string
+	Layout          idformat.Layout
+	Allocators      AllocatorFactory
+	PercentWhenFill float64
+}
+
+// Block is the block of multiplier and timestamp given out by allocator of the counter namespace of the namespace.
+type Block struct {
+	Namespace        string `json:"namespace,omitempty"`
+	CounterNamespace string `json:"counter_namespace,omitempty"`
+	Multiplier       int32  `json:"multiplier"`
+	Timestamp        int64  `json:"timestamp"`
+}
+
+// Registry keeps namespaces and creates storage of a namespace on its first request.
+type Registry struct {
+	namespaces map[string]Namespace
+	byPrefix   map[string]Namespace
+
+	mu       sync.Mutex
+	storages map[string]*Storage
+	closed   bool
+}
+
+// NewRegistry validates namespaces: names and prefixes must be unique, named namespaces must have a prefix
+// and layouts must be valid. Storages aren't created until they are requested.
+func NewRegistry(namespaces []Namespace) (*Registry, error) {
+	registry := &Registry{
+		namespaces: make(map[string]Namespace, len(namespaces)),
+		byPrefix:   make(map[string]Namespace, len(namespaces)),
+		storages:   make(map[string]*Storage, len(namespaces)),
+	}
+
+	for _, namespace := range namespaces {
+		if namespace.Name != "" && !namespaceNamePattern.MatchString(namespace.Name) {
+			return nil, fmt.Errorf("name of namespace must consist of lowercase letters, digits and dashes: %s", namespace.Name)
+		}
+
+		if _, ok := registry.namespaces[namespace.Name]; ok {
+			return nil, fmt.Errorf("namespace %q is specified more than once", namespace.Name)
+		}
+
+		if namespace.Name == "" && namespace.Prefix != "" {
+			return nil, fmt.Errorf("default namespace must not have a prefix")
+		}
+
+		if namespace.Name != "" && !namespacePrefixPattern.MatchString(namespace.Prefix) {
+			return nil, fmt.Errorf("prefix of namespace %s must consist of letters and digits: %q", namespace.Name, namespace.Prefix)
+		}
+
+		if other, ok := registry.byPrefix[namespace.Prefix]; ok {
+			return nil, fmt.Errorf("namespaces %q and %q have the same prefix %q", other.Name, namespace.Name, namespace.Prefix)
+		}
+
+		if namespace.Allocators == nil {
+			return nil, fmt.Errorf("allocator of namespace %q must not be nil", namespace.Name)
+		}
+
+		if err := namespace.Layout.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid id layout of namespace %q: %v", namespace.Name, err)
+		}
+
+		registry.namespaces[namespace.Name] = namespace
+		registry.byPrefix[namespace.Prefix] = namespace
+	}
+
+	return registry, nil
+}
+
+// Storage returns storage of the namespace and creates it on the first call.
+func (r *Registry) Storage(name string) (*Storage, error) {
+	namespace, ok := r.namespaces[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNamespace, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if storage, ok := r.storages[name]; ok {
+		return storage, nil
+	}
+
+	if r.closed {
+		return nil, ErrStorageClosed
+	}
+
+	storage, err := NewStorage(namespace.Allocators, namespace.Layout, namespace.PercentWhenFill)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage of namespace %q: %v", name, err)
+	}
+	storage.prefix = namespace.Prefix
+
+	r.storages[name] = storage
+
+	return storage, nil
+}
+
+// Close closes started storages, see Storage.Close. Storages of other namespaces aren't started after that.
+func (r *Registry) Close(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	storages := make([]*Storage, 0, len(r.storages))
+	for _, storage := range r.storages {
+		storages = append(storages, storage)
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	for _, storage := range storages {
+		if err := storage.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DecodeId finds namespace of the id by its prefix and decodes it with layout of the namespace.
+// Ids must be decoded with the same format and encoding of options. Storage of the namespace isn't created for that.
+func (r *Registry) DecodeId(id string, opts IdOptions) (string, idformat.ID, error) {
+	prefix, body, hasPrefix := strings.Cut(id, PrefixSeparator)
+	if !hasPrefix {
+		prefix, body = "", id
+	}
+
+	namespace, ok := r.byPrefix[prefix]
+	if !ok {
+		return "", idformat.ID{}, fmt.Errorf("unknown prefix of namespace: %q", prefix)
+	}
+
+	decodedId, err := decodeId(namespace.Layout, body, opts)
+	if err != nil {
+		return "", idformat.ID{}, err
+	}
+
+	return namespace.Name, decodedId, nil
+}
+
+// Stats returns stats of buffers of started storages keyed by name of namespace.
+func (r *Registry) Stats() map[string][]BufferStats {
+	r.mu.Lock()
+	storages := maps.Clone(r.storages)
+	r.mu.Unlock()
+
+	stats := make(map[string][]BufferStats, len(storages))
+	for name, storage := range storages {
+		stats[name] = storage.Stats()
+	}
+
+	return stats
+}
+
+// Health returns error of the first unhealthy started storage, see Storage.Health, or ErrStorageClosed
+// after Close.
+func (r *Registry) Health() error {
+	r.mu.Lock()
+	closed := r.closed
+	storages := maps.Clone(r.storages)
+	r.mu.Unlock()
+
+	if closed {
+		return ErrStorageClosed
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(storages)) {
+		if err := storages[name].Health(); err != nil {
+			return fmt.Errorf("namespace %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// BlockOfId decodes the id like DecodeId and returns the block it was generated from with time of its timestamp.
+func (r *Registry) BlockOfId(id string, opts IdOptions) (Block, time.Time, error) {
+	namespace, decodedId, err := r.DecodeId(id, opts)
+	if err != nil {
+		return Block{}, time.Time{}, err
+	}
+
+	sysType, _ := r.namespaces[namespace].Layout.GetSysTypes().Get(decodedId.SysType)
+
+	return Block{
+		Namespace:        namespace,
+		CounterNamespace: sysType.CounterNamespace,
+		Multiplier:       decodedId.Multiplier,
+		Timestamp:        decodedId.Timestamp,
+	}, decodedId.Time, nil
+}
+
+// ValidateId checks id in the format and encoding of options with layout of its namespace and returns the namespace.
+func (r *Registry) ValidateId(id string, opts IdOptions) (string, error) {
+	namespace, _, err := r.DecodeId(id, opts)
+	return namespace, err
+}
@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	generator_storage "id-generator/internal/generator-storage"
+)
+
+// File keeps entries in a local file, one JSON entry per line. Every node writes its own file,
+// so Find returns only blocks of this node.
+type File struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger file: %v", err)
+	}
+
+	return &File{path: path, file: file}, nil
+}
+
+func (f *File) Append(_ context.Context, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger entry: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write ledger entry: %v", err)
+	}
+
+	return nil
+}
+
+// Find scans the whole file.
+func (f *File) Find(ctx context.Context, block generator_storage.Block, _ time.Time) ([]Entry, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger file: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode ledger entry: %v", err)
+		}
+
+		if entry.Block == block {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger file: %v", err)
+	}
+
+	return entries, nil
+}
+
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
@@ -0,0 +1,107 @@
+package ledger
+
+import (
+	"context"
+	"log"
+	"time"
+
+	generator_storage "id-generator/internal/generator-storage"
+)
+
+// Events of blocks recorded in the ledger.
+const (
+	EventIssued   = "issued"
+	EventReleased = "released"
+)
+
+// Entry records what happened to the block on the node.
+type Entry struct {
+	Event string `json:"event"`
+	Node  string `json:"node"`
+	generator_storage.Block
+	At time.Time `json:"at"`
+}
+
+// Ledger is an append-only log of blocks given out to nodes.
+type Ledger interface {
+	Append(ctx context.Context, entry Entry) error
+	// Find returns entries of the block. Entries are looked for around time of the timestamp of the block,
+	// because blocks are given out in their timestamp.
+	Find(ctx context.Context, block generator_storage.Block, around time.Time) ([]Entry, error)
+	Close() error
+}
+
+// Audited records every block given out by the allocator to the ledger. Failed records are logged,
+// so the ledger being down doesn't stop ids from being issued.
+type Audited struct {
+	allocator generator_storage.Allocator
+	ledger    Ledger
+	node      string
+	namespace string
+	// counterNamespace is the counter namespace of the allocator within the namespace.
+	counterNamespace string
+}
+
+func NewAudited(allocator generator_storage.Allocator, ledger Ledger, node, namespace, counterNamespace string) *Audited {
+	return &Audited{
+		allocator:        allocator,
+		ledger:           ledger,
+		node:             node,
+		namespace:        namespace,
+		counterNamespace: counterNamespace,
+	}
+}
+
+// AuditedFactory returns factory, which wraps allocators of the factory with Audited.
+func AuditedFactory(
+	allocators generator_storage.AllocatorFactory, ledger Ledger, node, namespace string,
+) generator_storage.AllocatorFactory {
+	return func(counterNamespace string) (generator_storage.Allocator, error) {
+		allocator, err := allocators(counterNamespace)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewAudited(allocator, ledger, node, namespace, counterNamespace), nil
+	}
+}
+
+func (a *Audited) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	multiplier, timestamp, err = a.allocator.GetMultiplierAndTimestamp(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	a.record(ctx, EventIssued, multiplier, timestamp)
+
+	return multiplier, timestamp, nil
+}
+
+// ReleaseBlock releases the block to the wrapped allocator, see generator_storage.ReleaseTo, and records it.
+func (a *Audited) ReleaseBlock(ctx context.Context, multiplier int32, timestamp int64) error {
+	if err := generator_storage.ReleaseTo(ctx, a.allocator, multiplier, timestamp); err != nil {
+		return err
+	}
+
+	a.record(ctx, EventReleased, multiplier, timestamp)
+
+	return nil
+}
+
+func (a *Audited) record(ctx context.Context, event string, multiplier int32, timestamp int64) {
+	entry := Entry{
+		Event: event,
+		Node:  a.node,
+		Block: generator_storage.Block{
+			Namespace:        a.namespace,
+			CounterNamespace: a.counterNamespace,
+			Multiplier:       multiplier,
+			Timestamp:        timestamp,
+		},
+		At: time.Now().UTC(),
+	}
+
+	if err := a.ledger.Append(ctx, entry); err != nil {
+		log.Printf("failed to record %s block %d of timestamp %d to the ledger: %v", event, multiplier, timestamp, err)
+	}
+}
@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"id-generator/internal/allocator"
+	"id-generator/internal/cache"
+	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/pkg/idformat"
+)
+
+func TestAuditedRecordsBlocks(t *testing.T) {
+	fileLedger, err := NewFile(t.TempDir() + "/ledger.jsonl")
+	if err != nil {
+		t.Fatalf("failed to create file ledger: %v", err)
+	}
+	defer fileLedger.Close()
+
+	// the timestamp doesn't change during the test, so blocks of it can always be released
+	clock := idformat.Clock{Epoch: time.Now(), Resolution: 24 * time.Hour}
+
+	localAllocator, _ := allocator.NewLocal("10000", clock)
+	audited := NewAudited(localAllocator, fileLedger, "node-1", "tenant", "clients")
+
+	multiplier, timestamp, err := audited.GetMultiplierAndTimestamp(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	if _, _, err := audited.GetMultiplierAndTimestamp(context.Background()); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	block := generator_storage.Block{Namespace: "tenant", CounterNamespace: "clients", Multiplier: multiplier, Timestamp: timestamp}
+
+	entries, err := fileLedger.Find(context.Background(), block, clock.Time(timestamp))
+	if err != nil {
+		t.Fatalf("failed to find block: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Event != EventIssued || entries[0].Node != "node-1" {
+		t.Fatalf("expected one issued entry of node-1, got: %+v", entries)
+	}
+
+	if err := audited.ReleaseBlock(context.Background(), multiplier, timestamp); err != nil {
+		t.Fatalf("failed to release block: %v", err)
+	}
+
+	entries, err = fileLedger.Find(context.Background(), block, clock.Time(timestamp))
+	if err != nil {
+		t.Fatalf("failed to find block: %v", err)
+	}
+
+	if len(entries) != 2 || entries[1].Event != EventReleased || entries[1].Node != "node-1" {
+		t.Errorf("expected released entry of node-1, got: %+v", entries)
+	}
+
+	snowflakeLike := NewAudited(stubAllocator{}, fileLedger, "node-1", "", "")
+	if err := snowflakeLike.ReleaseBlock(context.Background(), 1, timestamp); err == nil {
+		t.Errorf("expected error of allocator, which doesn't take blocks back")
+	}
+}
+
+type stubAllocator struct{}
+
+func (stubAllocator) GetMultiplierAndTimestamp(context.Context) (int32, int64, error) {
+	return 1, 1, nil
+}
+
+func TestRedisLedger(t *testing.T) {
+	ctx := context.Background()
+
+	redisLedger, err := NewRedis("test-allocation-ledger", "")
+	if err != nil {
+		t.Fatalf("failed to create redis ledger: %v", err)
+	}
+
+	cache.Dragonfly.RawClient.Del(ctx, redisLedger.key)
+	defer cache.Dragonfly.RawClient.Del(ctx, redisLedger.key)
+
+	now := time.Now().UTC()
+	block := generator_storage.Block{Namespace: "tenant", Multiplier: 7, Timestamp: now.Unix()}
+
+	for _, node := range []string{"node-1", "node-2"} {
+		if err := redisLedger.Append(ctx, Entry{Event: EventIssued, Node: node, Block: block, At: now}); err != nil {
+			t.Fatalf("failed to append entry: %v", err)
+		}
+	}
+
+	other := block
+	other.Multiplier = 8
+	if err := redisLedger.Append(ctx, Entry{Event: EventIssued, Node: "node-1", Block: other, At: now}); err != nil {
+		t.Fatalf("failed to append entry: %v", err)
+	}
+
+	entries, err := redisLedger.Find(ctx, block, now)
+	if err != nil {
+		t.Fatalf("failed to find block: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Node != "node-1" || entries[1].Node != "node-2" || !entries[0].At.Equal(now) {
+		t.Errorf("expected block issued to node-1 and node-2, got: %+v", entries)
+	}
+
+	if entries, _ := redisLedger.Find(ctx, block, now.Add(time.Hour)); len(entries) != 0 {
+		t.Errorf("expected no entries an hour later, got: %+v", entries)
+	}
+}
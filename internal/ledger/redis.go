@@ -0,0 +1,127 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"id-generator/internal/cache"
+	generator_storage "id-generator/internal/generator-storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// findWindow is how far from time of the timestamp of the block Redis.Find looks for its entries. It covers
+// the clock of Redis being out of sync with the clock of the node and blocks released later in their timestamp.
+const findWindow = time.Minute
+
+// Redis keeps entries in a Redis stream shared by all nodes, ids of stream entries are time of Redis when they were added.
+// The key gets hash tag of cache.Dragonfly, so cache.Init must be called before NewRedis.
+type Redis struct {
+	key string
+	// maxLen trims the stream to about that number of entries, 0 keeps all of them.
+	maxLen int64
+}
+
+func NewRedis(key, maxLenStr string) (*Redis, error) {
+	if key == "" {
+		return nil, fmt.Errorf("redis key of the ledger must not be empty")
+	}
+
+	var maxLen int64
+	if maxLenStr != "" {
+		var err error
+		if maxLen, err = strconv.ParseInt(maxLenStr, 10, 64); err != nil || maxLen < 0 {
+			return nil, fmt.Errorf("max length of the ledger must be a non-negative number: %s", maxLenStr)
+		}
+	}
+
+	return &Redis{key: cache.Dragonfly.Key(key), maxLen: maxLen}, nil
+}
+
+func (r *Redis) Append(ctx context.Context, entry Entry) error {
+	err := cache.Dragonfly.RawClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.key,
+		MaxLen: r.maxLen,
+		Approx: r.maxLen > 0,
+		Values: []any{
+			"event", entry.Event,
+			"node", entry.Node,
+			"namespace", entry.Namespace,
+			"counter_namespace", entry.CounterNamespace,
+			"multiplier", entry.Multiplier,
+			"timestamp", entry.Timestamp,
+			"at", entry.At.Format(time.RFC3339Nano),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add ledger entry to redis stream: %v", err)
+	}
+
+	return nil
+}
+
+// Find reads entries added within findWindow around the time.
+func (r *Redis) Find(ctx context.Context, block generator_storage.Block, around time.Time) ([]Entry, error) {
+	messages, err := cache.Dragonfly.RawClient.XRange(
+		ctx, r.key,
+		strconv.FormatInt(around.Add(-findWindow).UnixMilli(), 10),
+		strconv.FormatInt(around.Add(findWindow).UnixMilli(), 10),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger entries from redis stream: %v", err)
+	}
+
+	var entries []Entry
+	for _, message := range messages {
+		entry, err := entryOfMessage(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode ledger entry %s: %v", message.ID, err)
+		}
+
+		if entry.Block == block {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *Redis) Close() error {
+	return nil
+}
+
+func entryOfMessage(message redis.XMessage) (Entry, error) {
+	value := func(field string) string {
+		str, _ := message.Values[field].(string)
+		return str
+	}
+
+	multiplier, err := strconv.ParseInt(value("multiplier"), 10, 32)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid multiplier: %v", err)
+	}
+
+	timestamp, err := strconv.ParseInt(value("timestamp"), 10, 64)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid timestamp: %v", err)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, value("at"))
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid time: %v", err)
+	}
+
+	return Entry{
+		Event: value("event"),
+		Node:  value("node"),
+		Block: generator_storage.Block{
+			Namespace:        value("namespace"),
+			CounterNamespace: value("counter_namespace"),
+			Multiplier:       int32(multiplier),
+			Timestamp:        timestamp,
+		},
+		At: at,
+	}, nil
+}
@@ -2,86 +2,121 @@ package master_server
 
 import (
 	"context"
-	"crypto/sha1"
-	"encoding/hex"
 	"fmt"
-	"log"
-	"math"
-	"os"
 	"strconv"
+	"sync"
 	"time"
 
-	"id-generator/internal/cache"
+	"id-generator/internal/allocator"
+	"id-generator/internal/metrics"
+	"id-generator/internal/tracing"
+	"id-generator/pkg/idformat"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("id-generator/internal/master-server")
+
 type MasterServer struct {
-	scriptSha            string
 	redisCounterKey      string
 	redisTimestampKey    string
-	maxAllowedMultiplier int
+	maxAllowedMultiplier string
+	clock                idformat.Clock
+	leaser               *allocator.RedisLeaser
+
+	mu sync.Mutex
+	// allocators are keyed by counter namespace, they are created on the first request of the namespace.
+	allocators map[string]*allocator.Redis
 }
 
-func NewMasterServer(redisCounterKey, redisTimestampKey, maxAllowedMultiplierStr, freeDigitsForIdsStr string) (*MasterServer, error) {
-	if redisCounterKey == "" || redisTimestampKey == "" {
-		return nil, fmt.Errorf("redis keys REDIS_COUNTER_KEY or REDIS_TIMESTAMP_KEY must not be empty")
+// NewMasterServer creates master server, which allocates blocks and leases worker ids of snowflake mode with the leaser.
+func NewMasterServer(
+	redisCounterKey, redisTimestampKey string, layout idformat.Layout, leaser *allocator.RedisLeaser,
+) (*MasterServer, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid id layout: %v", err)
 	}
 
-	maxAllowedMultiplier, err := strconv.Atoi(maxAllowedMultiplierStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	if layout.MaxAllowedMultiplier%leaser.Workers() != 0 {
+		return nil, fmt.Errorf("MAX_ALLOWED_MULTIPLIER must be a multiple of SNOWFLAKE_WORKERS")
 	}
 
-	freeDigitsForIds, err := strconv.Atoi(freeDigitsForIdsStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert to int FREE_DIGITS_FOR_IDS")
+	ms := &MasterServer{
+		redisCounterKey:      redisCounterKey,
+		redisTimestampKey:    redisTimestampKey,
+		maxAllowedMultiplier: strconv.Itoa(layout.MaxAllowedMultiplier),
+		clock:                layout.Clock,
+		leaser:               leaser,
+		allocators:           make(map[string]*allocator.Redis),
 	}
 
-	maxNumberOfIds := math.Pow10(freeDigitsForIds)
-	if maxNumberOfIds < float64(maxAllowedMultiplier) {
-		return nil, fmt.Errorf("10^(FREE_DIGITS_FOR_IDS) must not be less than MAX_ALLOWED_MULTIPLIER")
+	if _, err := ms.getAllocator(""); err != nil {
+		return nil, err
 	}
 
-	return &MasterServer{"", redisCounterKey, redisTimestampKey, maxAllowedMultiplier}, nil
+	return ms, nil
 }
 
-func (ms *MasterServer) LoadRedisScript() {
-	bytes, err := os.ReadFile("redis-script.lua")
-	if err != nil {
-		log.Fatalf("failed to load script.lua file: %v", err)
-	}
+// Clock returns clock, timestamps of blocks are counted with.
+func (ms *MasterServer) Clock() idformat.Clock {
+	return ms.clock
+}
 
-	hash := sha1.New()
-	hash.Write(bytes)
-	scriptSha := hex.EncodeToString(hash.Sum(nil))
+// MaxAllowedMultiplier returns number of blocks of every timestamp.
+func (ms *MasterServer) MaxAllowedMultiplier() int {
+	multiplier, _ := strconv.Atoi(ms.maxAllowedMultiplier)
+	return multiplier
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+// Leaser returns leaser of worker ids of generator nodes in snowflake mode.
+func (ms *MasterServer) Leaser() *allocator.RedisLeaser {
+	return ms.leaser
+}
 
-	results, err := cache.Dragonfly.RawClient.ScriptExists(ctx, scriptSha).Result()
+// GetMultiplierAndTimestamp allocates block from counter of the namespace, empty namespace is the default counter.
+// Metrics of blocks get the namespace as counter_namespace label.
+func (ms *MasterServer) GetMultiplierAndTimestamp(ctx context.Context, namespace string) (multiplier int32, timestamp int64, err error) {
+	ctx, span := tracer.Start(ctx, "MasterServer.GetMultiplierAndTimestamp", trace.WithAttributes(attribute.String("namespace", namespace)))
+	defer func() {
+		span.SetAttributes(attribute.Int("multiplier", int(multiplier)), attribute.Int64("timestamp", timestamp))
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	redisAllocator, err := ms.getAllocator(namespace)
 	if err != nil {
-		fmt.Printf("failed to check if script exists by sha representation: %v\n", err)
+		return 0, 0, err
 	}
 
-	ms.scriptSha = scriptSha
+	startedAt := time.Now()
 
-	if results[0] {
-		return
-	}
+	multiplier, timestamp, err = redisAllocator.GetMultiplierAndTimestamp(ctx)
+	metrics.ObserveBlock("", namespace, startedAt, err)
 
-	_, err = cache.Dragonfly.RawClient.ScriptLoad(ctx, string(bytes)).Result()
-	if err != nil {
-		log.Fatalf("failed to load script: %v", err)
-	}
+	return multiplier, timestamp, err
 }
 
-func (ms *MasterServer) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
-	result, err := cache.Dragonfly.RawClient.EvalSha(
-		ctx,
-		ms.scriptSha, []string{ms.redisCounterKey, ms.redisTimestampKey}, ms.maxAllowedMultiplier,
-	).Int64Slice()
+func (ms *MasterServer) getAllocator(namespace string) (*allocator.Redis, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if redisAllocator, ok := ms.allocators[namespace]; ok {
+		return redisAllocator, nil
+	}
+
+	redisAllocator, err := allocator.NewRedis(
+		allocator.NamespacedKey(ms.redisCounterKey, namespace),
+		allocator.NamespacedKey(ms.redisTimestampKey, namespace),
+		ms.maxAllowedMultiplier,
+		ms.clock,
+	)
 	if err != nil {
-		return 0, 0, fmt.Errorf("there was an error while getting multiplier or timestamp: %v", err)
+		return nil, err
 	}
 
-	return int32(result[0]), result[1], nil
+	ms.allocators[namespace] = redisAllocator
+
+	return redisAllocator, nil
 }
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"id-generator/internal/allocator"
+	generator_storage "id-generator/internal/generator-storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry keeps metrics of the process with metrics of Go runtime and the process itself.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	blocks = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "id_generator_blocks_total",
+		Help: "Number of blocks given out by allocators.",
+	}, []string{"namespace", "counter_namespace"})
+
+	blockDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "id_generator_block_allocation_duration_seconds",
+		Help:    "Duration of allocation of one block, including retries and waiting for the next timestamp.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"namespace", "counter_namespace"})
+
+	blockErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "id_generator_block_allocation_errors_total",
+		Help: "Number of failed allocations of blocks by reason: clock_regression, lease_lost, timeout or error.",
+	}, []string{"namespace", "counter_namespace", "reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves metrics of Registry in the format of Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveBlock records allocation of the block, which was started at the time.
+func ObserveBlock(namespace, counterNamespace string, startedAt time.Time, err error) {
+	blockDuration.WithLabelValues(namespace, counterNamespace).Observe(time.Since(startedAt).Seconds())
+
+	if err != nil {
+		blockErrors.WithLabelValues(namespace, counterNamespace, errorReason(err)).Inc()
+		return
+	}
+
+	blocks.WithLabelValues(namespace, counterNamespace).Inc()
+}
+
+func errorReason(err error) string {
+	var regressionErr *allocator.ClockRegressionError
+
+	switch {
+	case errors.As(err, &regressionErr):
+		return "clock_regression"
+	case errors.Is(err, allocator.ErrLeaseLost):
+		return "lease_lost"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "timeout"
+	}
+
+	return "error"
+}
+
+// Instrumented records metrics of every block given out by the allocator.
+type Instrumented struct {
+	allocator        generator_storage.Allocator
+	namespace        string
+	counterNamespace string
+}
+
+// InstrumentedFactory returns factory, which wraps allocators of the factory with Instrumented.
+func InstrumentedFactory(allocators generator_storage.AllocatorFactory, namespace string) generator_storage.AllocatorFactory {
+	return func(counterNamespace string) (generator_storage.Allocator, error) {
+		allocator, err := allocators(counterNamespace)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Instrumented{allocator: allocator, namespace: namespace, counterNamespace: counterNamespace}, nil
+	}
+}
+
+func (i *Instrumented) GetMultiplierAndTimestamp(ctx context.Context) (multiplier int32, timestamp int64, err error) {
+	startedAt := time.Now()
+
+	multiplier, timestamp, err = i.allocator.GetMultiplierAndTimestamp(ctx)
+	ObserveBlock(i.namespace, i.counterNamespace, startedAt, err)
+
+	return multiplier, timestamp, err
+}
+
+// ReleaseBlock releases the block to the wrapped allocator, see generator_storage.ReleaseTo.
+func (i *Instrumented) ReleaseBlock(ctx context.Context, multiplier int32, timestamp int64) error {
+	return generator_storage.ReleaseTo(ctx, i.allocator, multiplier, timestamp)
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"id-generator/internal/allocator"
+	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/pkg/idformat"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// gather returns sum of values of the metric with the label value, all label values if it is empty.
+func gather(t *testing.T, name, label, value string) float64 {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	sum := 0.0
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if label != "" && labelValue(metric, label) != value {
+				continue
+			}
+
+			switch {
+			case metric.GetCounter() != nil:
+				sum += metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				sum += metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				sum += float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	return sum
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+
+	return ""
+}
+
+func TestStorageMetrics(t *testing.T) {
+	registry, err := generator_storage.NewRegistry([]generator_storage.Namespace{{
+		Name:   "metrics-test",
+		Prefix: "MT",
+		Layout: idformat.DefaultLayout,
+		Allocators: InstrumentedFactory(func(string) (generator_storage.Allocator, error) {
+			return allocator.NewLocal("10000", idformat.DefaultClock)
+		}, "metrics-test"),
+		PercentWhenFill: 0.3,
+	}})
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	if err := RegisterStorage(registry); err != nil {
+		t.Fatalf("failed to register metrics of storage: %v", err)
+	}
+
+	storage, err := registry.Storage("metrics-test")
+	if err != nil {
+		t.Fatalf("failed to get storage: %v", err)
+	}
+
+	if _, err := storage.GetUniqueIdsWithType(context.Background(), "Vendor", generator_storage.IdOptions{}, 10); err != nil {
+		t.Fatalf("failed to get ids: %v", err)
+	}
+
+	if issued := gather(t, "id_generator_ids_issued_total", "namespace", "metrics-test"); issued != 10 {
+		t.Errorf("expected 10 issued ids, got %v", issued)
+	}
+
+	if blocks := gather(t, "id_generator_blocks_total", "namespace", "metrics-test"); blocks < 1 {
+		t.Errorf("expected blocks to be counted, got %v", blocks)
+	}
+
+	if capacity := gather(t, "id_generator_buffer_capacity", "sys_type", "Vendor"); capacity == 0 {
+		t.Errorf("expected capacity of Vendor buffer")
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{namespace}/stats", func(res http.ResponseWriter, _ *http.Request) {
+		res.WriteHeader(http.StatusNotFound)
+	})
+	mux.Handle("/metrics", Handler())
+
+	server := httptest.NewServer(HTTPMiddleware(mux))
+	defer server.Close()
+
+	for _, namespace := range []string{"a", "b"} {
+		res, err := http.Get(server.URL + "/" + namespace + "/stats")
+		if err != nil {
+			t.Fatalf("failed to request stats: %v", err)
+		}
+		res.Body.Close()
+	}
+
+	if requests := gather(t, "id_generator_http_request_duration_seconds", "route", "/{namespace}/stats"); requests != 2 {
+		t.Errorf("expected 2 requests of the route, got %v", requests)
+	}
+
+	res, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to request metrics: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	if !strings.Contains(string(body), `id_generator_http_request_duration_seconds_count{code="404",route="/{namespace}/stats"} 2`) {
+		t.Errorf("expected requests of the route in metrics, got:\n%s", body)
+	}
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var requestBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+var (
+	grpcDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "id_generator_grpc_request_duration_seconds",
+		Help:    "Duration of gRPC requests by method and status code.",
+		Buckets: requestBuckets,
+	}, []string{"method", "code"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "id_generator_http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route and status code.",
+		Buckets: requestBuckets,
+	}, []string{"route", "code"})
+)
+
+// UnaryServerInterceptor records duration and status code of gRPC requests.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		startedAt := time.Now()
+
+		resp, err := handler(ctx, req)
+		grpcDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).Observe(time.Since(startedAt).Seconds())
+
+		return resp, err
+	}
+}
+
+// statusRecorder keeps status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// HTTPMiddleware records duration and status code of requests to the handler. Routes are patterns of http.ServeMux,
+// so requests of named namespaces don't get a label each.
+func HTTPMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		startedAt := time.Now()
+		recorder := &statusRecorder{ResponseWriter: res, status: http.StatusOK}
+
+		handler.ServeHTTP(recorder, req)
+
+		route := req.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		httpDuration.WithLabelValues(route, strconv.Itoa(recorder.status)).Observe(time.Since(startedAt).Seconds())
+	})
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	generator_storage "id-generator/internal/generator-storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var bufferLabels = []string{"namespace", "sys_type", "counter_namespace"}
+
+var (
+	bufferedDesc = prometheus.NewDesc(
+		"id_generator_buffer_ids", "Number of ids ready to be issued.", bufferLabels, nil,
+	)
+	capacityDesc = prometheus.NewDesc(
+		"id_generator_buffer_capacity", "Maximum number of buffered ids.", bufferLabels, nil,
+	)
+	fillRatioDesc = prometheus.NewDesc(
+		"id_generator_buffer_fill_ratio", "Ratio of buffered ids to capacity of the buffer.", bufferLabels, nil,
+	)
+	issuedDesc = prometheus.NewDesc(
+		"id_generator_ids_issued_total", "Number of issued ids.", bufferLabels, nil,
+	)
+	refillsDesc = prometheus.NewDesc(
+		"id_generator_buffer_refills_total", "Number of blocks put into the buffer by refills.", bufferLabels, nil,
+	)
+	refillFailuresDesc = prometheus.NewDesc(
+		"id_generator_buffer_refill_failures_total", "Number of failed attempts to refill the buffer.", bufferLabels, nil,
+	)
+	waitsDesc = prometheus.NewDesc(
+		"id_generator_buffer_waits_total", "Number of requests, which found the buffer empty.", bufferLabels, nil,
+	)
+	unavailableDesc = prometheus.NewDesc(
+		"id_generator_buffer_unavailable", "1 while refills of the buffer fail and it has no ids, otherwise 0.", bufferLabels, nil,
+	)
+)
+
+// storageCollector collects stats of buffers of started storages of the registry on every scrape.
+type storageCollector struct {
+	registry *generator_storage.Registry
+}
+
+// RegisterStorage registers metrics of buffers of storages of the registry.
+func RegisterStorage(registry *generator_storage.Registry) error {
+	return Registry.Register(&storageCollector{registry: registry})
+}
+
+func (c *storageCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		bufferedDesc, capacityDesc, fillRatioDesc, issuedDesc, refillsDesc, refillFailuresDesc, waitsDesc, unavailableDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *storageCollector) Collect(ch chan<- prometheus.Metric) {
+	for namespace, stats := range c.registry.Stats() {
+		for _, buffer := range stats {
+			labels := []string{namespace, buffer.SysType, buffer.CounterNamespace}
+
+			unavailable := 0.0
+			if buffer.Unavailable {
+				unavailable = 1
+			}
+
+			ch <- prometheus.MustNewConstMetric(bufferedDesc, prometheus.GaugeValue, float64(buffer.Buffered), labels...)
+			ch <- prometheus.MustNewConstMetric(capacityDesc, prometheus.GaugeValue, float64(buffer.Capacity), labels...)
+			ch <- prometheus.MustNewConstMetric(
+				fillRatioDesc, prometheus.GaugeValue, float64(buffer.Buffered)/float64(buffer.Capacity), labels...,
+			)
+			ch <- prometheus.MustNewConstMetric(issuedDesc, prometheus.CounterValue, float64(buffer.Issued), labels...)
+			ch <- prometheus.MustNewConstMetric(refillsDesc, prometheus.CounterValue, float64(buffer.Refills), labels...)
+			ch <- prometheus.MustNewConstMetric(refillFailuresDesc, prometheus.CounterValue, float64(buffer.RefillFailures), labels...)
+			ch <- prometheus.MustNewConstMetric(waitsDesc, prometheus.CounterValue, float64(buffer.Waits), labels...)
+			ch <- prometheus.MustNewConstMetric(unavailableDesc, prometheus.GaugeValue, unavailable, labels...)
+		}
+	}
+}
@@ -73,9 +73,65 @@ func (SysType) EnumDescriptor() ([]byte, []int) {
 	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{0}
 }
 
+type IdFormat int32
+
+const (
+	IdFormat_DECIMAL IdFormat = 0
+	// timestamp, sys type and tail packed into 63 bits, returned in numeric_id
+	IdFormat_INT64 IdFormat = 1
+	// UUIDv7 of RFC 9562 with time of the id, tail and sys type instead of random bits
+	IdFormat_UUID_V7 IdFormat = 2
+	// ULID with time of the id, tail and sys type instead of random bits
+	IdFormat_ULID IdFormat = 3
+)
+
+// Enum value maps for IdFormat.
+var (
+	IdFormat_name = map[int32]string{
+		0: "DECIMAL",
+		1: "INT64",
+		2: "UUID_V7",
+		3: "ULID",
+	}
+	IdFormat_value = map[string]int32{
+		"DECIMAL": 0,
+		"INT64":   1,
+		"UUID_V7": 2,
+		"ULID":    3,
+	}
+)
+
+func (x IdFormat) Enum() *IdFormat {
+	p := new(IdFormat)
+	*p = x
+	return p
+}
+
+func (x IdFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IdFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_protobuf_id_generator_proto_enumTypes[1].Descriptor()
+}
+
+func (IdFormat) Type() protoreflect.EnumType {
+	return &file_protobuf_id_generator_proto_enumTypes[1]
+}
+
+func (x IdFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IdFormat.Descriptor instead.
+func (IdFormat) EnumDescriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{1}
+}
+
 type UniqueIdReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	NumericId     int64                  `protobuf:"varint,2,opt,name=numeric_id,json=numericId,proto3" json:"numeric_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,9 +173,25 @@ func (x *UniqueIdReply) GetId() string {
 	return ""
 }
 
+func (x *UniqueIdReply) GetNumericId() int64 {
+	if x != nil {
+		return x.NumericId
+	}
+	return 0
+}
+
 type UniqueIdRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SysType       SysType                `protobuf:"varint,1,opt,name=sys_type,json=sysType,proto3,enum=id_generator.SysType" json:"sys_type,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SysType SysType                `protobuf:"varint,1,opt,name=sys_type,json=sysType,proto3,enum=id_generator.SysType" json:"sys_type,omitempty"`
+	Format  IdFormat               `protobuf:"varint,2,opt,name=format,proto3,enum=id_generator.IdFormat" json:"format,omitempty"`
+	// name of sys type from the registry, used instead of sys_type when set
+	SysTypeName string `protobuf:"bytes,3,opt,name=sys_type_name,json=sysTypeName,proto3" json:"sys_type_name,omitempty"`
+	// ids with the same box_key get the same sys type digit, see README
+	BoxKey string `protobuf:"bytes,4,opt,name=box_key,json=boxKey,proto3" json:"box_key,omitempty"`
+	// name of namespace, empty for the default one
+	Namespace string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// base32, base58 or base62, decimal ids if empty
+	Encoding      string `protobuf:"bytes,6,opt,name=encoding,proto3" json:"encoding,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -161,29 +233,977 @@ func (x *UniqueIdRequest) GetSysType() SysType {
 	return SysType_Unknown
 }
 
+func (x *UniqueIdRequest) GetFormat() IdFormat {
+	if x != nil {
+		return x.Format
+	}
+	return IdFormat_DECIMAL
+}
+
+func (x *UniqueIdRequest) GetSysTypeName() string {
+	if x != nil {
+		return x.SysTypeName
+	}
+	return ""
+}
+
+func (x *UniqueIdRequest) GetBoxKey() string {
+	if x != nil {
+		return x.BoxKey
+	}
+	return ""
+}
+
+func (x *UniqueIdRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *UniqueIdRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+type UniqueIdsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	NumericIds    []int64                `protobuf:"varint,2,rep,packed,name=numeric_ids,json=numericIds,proto3" json:"numeric_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueIdsReply) Reset() {
+	*x = UniqueIdsReply{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueIdsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueIdsReply) ProtoMessage() {}
+
+func (x *UniqueIdsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueIdsReply.ProtoReflect.Descriptor instead.
+func (*UniqueIdsReply) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UniqueIdsReply) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *UniqueIdsReply) GetNumericIds() []int64 {
+	if x != nil {
+		return x.NumericIds
+	}
+	return nil
+}
+
+type UniqueIdsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	SysType SysType                `protobuf:"varint,1,opt,name=sys_type,json=sysType,proto3,enum=id_generator.SysType" json:"sys_type,omitempty"`
+	Count   int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Format  IdFormat               `protobuf:"varint,3,opt,name=format,proto3,enum=id_generator.IdFormat" json:"format,omitempty"`
+	// name of sys type from the registry, used instead of sys_type when set
+	SysTypeName string `protobuf:"bytes,4,opt,name=sys_type_name,json=sysTypeName,proto3" json:"sys_type_name,omitempty"`
+	// ids with the same box_key get the same sys type digit, see README
+	BoxKey string `protobuf:"bytes,5,opt,name=box_key,json=boxKey,proto3" json:"box_key,omitempty"`
+	// name of namespace, empty for the default one
+	Namespace string `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// base32, base58 or base62, decimal ids if empty
+	Encoding      string `protobuf:"bytes,7,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueIdsRequest) Reset() {
+	*x = UniqueIdsRequest{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueIdsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueIdsRequest) ProtoMessage() {}
+
+func (x *UniqueIdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueIdsRequest.ProtoReflect.Descriptor instead.
+func (*UniqueIdsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UniqueIdsRequest) GetSysType() SysType {
+	if x != nil {
+		return x.SysType
+	}
+	return SysType_Unknown
+}
+
+func (x *UniqueIdsRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *UniqueIdsRequest) GetFormat() IdFormat {
+	if x != nil {
+		return x.Format
+	}
+	return IdFormat_DECIMAL
+}
+
+func (x *UniqueIdsRequest) GetSysTypeName() string {
+	if x != nil {
+		return x.SysTypeName
+	}
+	return ""
+}
+
+func (x *UniqueIdsRequest) GetBoxKey() string {
+	if x != nil {
+		return x.BoxKey
+	}
+	return ""
+}
+
+func (x *UniqueIdsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *UniqueIdsRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+type DecodeIdReply struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp    int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	SysType      SysType                `protobuf:"varint,2,opt,name=sys_type,json=sysType,proto3,enum=id_generator.SysType" json:"sys_type,omitempty"`
+	SysTypeDigit int32                  `protobuf:"varint,3,opt,name=sys_type_digit,json=sysTypeDigit,proto3" json:"sys_type_digit,omitempty"`
+	Multiplier   int32                  `protobuf:"varint,4,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	Offset       int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	SysTypeName  string                 `protobuf:"bytes,6,opt,name=sys_type_name,json=sysTypeName,proto3" json:"sys_type_name,omitempty"`
+	// namespace is found by prefix of the id
+	Namespace     string `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeIdReply) Reset() {
+	*x = DecodeIdReply{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeIdReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeIdReply) ProtoMessage() {}
+
+func (x *DecodeIdReply) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeIdReply.ProtoReflect.Descriptor instead.
+func (*DecodeIdReply) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecodeIdReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DecodeIdReply) GetSysType() SysType {
+	if x != nil {
+		return x.SysType
+	}
+	return SysType_Unknown
+}
+
+func (x *DecodeIdReply) GetSysTypeDigit() int32 {
+	if x != nil {
+		return x.SysTypeDigit
+	}
+	return 0
+}
+
+func (x *DecodeIdReply) GetMultiplier() int32 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *DecodeIdReply) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DecodeIdReply) GetSysTypeName() string {
+	if x != nil {
+		return x.SysTypeName
+	}
+	return ""
+}
+
+func (x *DecodeIdReply) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type DecodeIdRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// encoding of the id, decimal if empty
+	Encoding string `protobuf:"bytes,2,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// format of the id, UUID_V7 and ULID ids need it, others are decoded as decimal ones
+	Format        IdFormat `protobuf:"varint,3,opt,name=format,proto3,enum=id_generator.IdFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeIdRequest) Reset() {
+	*x = DecodeIdRequest{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeIdRequest) ProtoMessage() {}
+
+func (x *DecodeIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeIdRequest.ProtoReflect.Descriptor instead.
+func (*DecodeIdRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecodeIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DecodeIdRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *DecodeIdRequest) GetFormat() IdFormat {
+	if x != nil {
+		return x.Format
+	}
+	return IdFormat_DECIMAL
+}
+
+type SysTypeInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MinDigit      int32                  `protobuf:"varint,2,opt,name=min_digit,json=minDigit,proto3" json:"min_digit,omitempty"`
+	MaxDigit      int32                  `protobuf:"varint,3,opt,name=max_digit,json=maxDigit,proto3" json:"max_digit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SysTypeInfo) Reset() {
+	*x = SysTypeInfo{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SysTypeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SysTypeInfo) ProtoMessage() {}
+
+func (x *SysTypeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SysTypeInfo.ProtoReflect.Descriptor instead.
+func (*SysTypeInfo) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SysTypeInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SysTypeInfo) GetMinDigit() int32 {
+	if x != nil {
+		return x.MinDigit
+	}
+	return 0
+}
+
+func (x *SysTypeInfo) GetMaxDigit() int32 {
+	if x != nil {
+		return x.MaxDigit
+	}
+	return 0
+}
+
+type ListSysTypesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SysTypes      []*SysTypeInfo         `protobuf:"bytes,1,rep,name=sys_types,json=sysTypes,proto3" json:"sys_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSysTypesReply) Reset() {
+	*x = ListSysTypesReply{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSysTypesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSysTypesReply) ProtoMessage() {}
+
+func (x *ListSysTypesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSysTypesReply.ProtoReflect.Descriptor instead.
+func (*ListSysTypesReply) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSysTypesReply) GetSysTypes() []*SysTypeInfo {
+	if x != nil {
+		return x.SysTypes
+	}
+	return nil
+}
+
+type ListSysTypesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name of namespace, empty for the default one
+	Namespace     string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSysTypesRequest) Reset() {
+	*x = ListSysTypesRequest{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSysTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSysTypesRequest) ProtoMessage() {}
+
+func (x *ListSysTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSysTypesRequest.ProtoReflect.Descriptor instead.
+func (*ListSysTypesRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSysTypesRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ValidateIdReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// reason why the id is invalid, e.g. mismatched check digit
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Namespace     string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateIdReply) Reset() {
+	*x = ValidateIdReply{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateIdReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateIdReply) ProtoMessage() {}
+
+func (x *ValidateIdReply) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateIdReply.ProtoReflect.Descriptor instead.
+func (*ValidateIdReply) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ValidateIdReply) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateIdReply) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ValidateIdReply) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ValidateIdRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// encoding of the id, decimal if empty
+	Encoding string `protobuf:"bytes,2,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// format of the id, UUID_V7 and ULID ids need it, others are decoded as decimal ones
+	Format        IdFormat `protobuf:"varint,3,opt,name=format,proto3,enum=id_generator.IdFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateIdRequest) Reset() {
+	*x = ValidateIdRequest{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateIdRequest) ProtoMessage() {}
+
+func (x *ValidateIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateIdRequest.ProtoReflect.Descriptor instead.
+func (*ValidateIdRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ValidateIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ValidateIdRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *ValidateIdRequest) GetFormat() IdFormat {
+	if x != nil {
+		return x.Format
+	}
+	return IdFormat_DECIMAL
+}
+
+type LedgerEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// issued or released
+	Event string `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Node  string `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	// time of the event in unix milliseconds
+	AtMs          int64 `protobuf:"varint,3,opt,name=at_ms,json=atMs,proto3" json:"at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerEntry) Reset() {
+	*x = LedgerEntry{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntry) ProtoMessage() {}
+
+func (x *LedgerEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntry.ProtoReflect.Descriptor instead.
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LedgerEntry) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAtMs() int64 {
+	if x != nil {
+		return x.AtMs
+	}
+	return 0
+}
+
+type FindBlockReply struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Namespace        string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	CounterNamespace string                 `protobuf:"bytes,2,opt,name=counter_namespace,json=counterNamespace,proto3" json:"counter_namespace,omitempty"`
+	Multiplier       int32                  `protobuf:"varint,3,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	Timestamp        int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// a block issued more than once has more than one issued entry
+	Entries       []*LedgerEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindBlockReply) Reset() {
+	*x = FindBlockReply{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindBlockReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindBlockReply) ProtoMessage() {}
+
+func (x *FindBlockReply) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindBlockReply.ProtoReflect.Descriptor instead.
+func (*FindBlockReply) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FindBlockReply) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *FindBlockReply) GetCounterNamespace() string {
+	if x != nil {
+		return x.CounterNamespace
+	}
+	return ""
+}
+
+func (x *FindBlockReply) GetMultiplier() int32 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *FindBlockReply) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *FindBlockReply) GetEntries() []*LedgerEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type FindBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// encoding of the id, decimal if empty
+	Encoding string `protobuf:"bytes,2,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// format of the id, UUID_V7 and ULID ids need it, others are decoded as decimal ones
+	Format        IdFormat `protobuf:"varint,3,opt,name=format,proto3,enum=id_generator.IdFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindBlockRequest) Reset() {
+	*x = FindBlockRequest{}
+	mi := &file_protobuf_id_generator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindBlockRequest) ProtoMessage() {}
+
+func (x *FindBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_id_generator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindBlockRequest.ProtoReflect.Descriptor instead.
+func (*FindBlockRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_id_generator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FindBlockRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FindBlockRequest) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *FindBlockRequest) GetFormat() IdFormat {
+	if x != nil {
+		return x.Format
+	}
+	return IdFormat_DECIMAL
+}
+
 var File_protobuf_id_generator_proto protoreflect.FileDescriptor
 
 var file_protobuf_id_generator_proto_rawDesc = string([]byte{
 	0x0a, 0x1b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x69, 0x64, 0x2d, 0x67, 0x65,
 	0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x69,
-	0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x1f, 0x0a, 0x0d, 0x55,
+	0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x3e, 0x0a, 0x0d, 0x55,
 	0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x0e, 0x0a, 0x02,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x43, 0x0a, 0x0f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x49, 0x64, 0x22, 0xea, 0x01, 0x0a, 0x0f,
 	0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x30, 0x0a, 0x08, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0e, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
 	0x2e, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x73, 0x79, 0x73, 0x54, 0x79, 0x70,
-	0x65, 0x2a, 0x38, 0x0a, 0x07, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07,
-	0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x56, 0x65, 0x6e,
-	0x64, 0x6f, 0x72, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x6f, 0x78, 0x10, 0x02, 0x12, 0x0b,
-	0x0a, 0x07, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x10, 0x03, 0x32, 0x58, 0x0a, 0x09, 0x47,
-	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x4b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55,
-	0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e,
-	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65,
-	0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x0d, 0x5a, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x49, 0x64, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x54, 0x79, 0x70,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x6f, 0x78, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x78, 0x4b, 0x65, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x43, 0x0a, 0x0e, 0x55, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x49, 0x64, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x03, 0x52, 0x0a, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x49, 0x64, 0x73, 0x22, 0x81, 0x02,
+	0x0a, 0x10, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x73, 0x79, 0x73,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x69, 0x64, 0x5f,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x49, 0x64, 0x46, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x79,
+	0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x62, 0x6f, 0x78, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x62, 0x6f, 0x78, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x22, 0xff, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x73, 0x79, 0x73, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f,
+	0x64, 0x69, 0x67, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x73, 0x79, 0x73,
+	0x54, 0x79, 0x70, 0x65, 0x44, 0x69, 0x67, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x79, 0x73, 0x54, 0x79, 0x70,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x22, 0x6d, 0x0a, 0x0f, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x16, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x49, 0x64, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x22, 0x5b, 0x0a, 0x0b, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x69, 0x67,
+	0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x44, 0x69, 0x67,
+	0x69, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x67, 0x69, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x67, 0x69, 0x74, 0x22,
+	0x4b, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x36, 0x0a, 0x09, 0x73, 0x79, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x08, 0x73, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0x33, 0x0a, 0x13,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x22, 0x5d, 0x0a, 0x0f, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x22, 0x6f, 0x0a, 0x11, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x16, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x49, 0x64, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x22, 0x4c, 0x0a, 0x0b, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x61, 0x74,
+	0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x61, 0x74, 0x4d, 0x73, 0x22,
+	0xce, 0x01, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x33, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x69,
+	0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x64, 0x67,
+	0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0x6e, 0x0a, 0x10, 0x46, 0x69, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x16, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x49, 0x64, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x2a, 0x38, 0x0a, 0x07, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x56, 0x65, 0x6e, 0x64,
+	0x6f, 0x72, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x6f, 0x78, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x10, 0x03, 0x2a, 0x39, 0x0a, 0x08, 0x49, 0x64,
+	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x43, 0x49, 0x4d, 0x41,
+	0x4c, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x01, 0x12, 0x0b,
+	0x0a, 0x07, 0x55, 0x55, 0x49, 0x44, 0x5f, 0x56, 0x37, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x55,
+	0x4c, 0x49, 0x44, 0x10, 0x03, 0x32, 0xe5, 0x03, 0x0a, 0x09, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x4b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x49, 0x64, 0x12, 0x1d, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4e, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x73,
+	0x12, 0x1e, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x49, 0x64, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x48, 0x0a, 0x08, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x2e, 0x69,
+	0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x65, 0x63, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x69, 0x64,
+	0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64,
+	0x65, 0x49, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0c, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x69, 0x64, 0x5f,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x79,
+	0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x79, 0x73, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4e, 0x0a, 0x0a, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1f,
+	0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x56,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4b, 0x0a, 0x09, 0x46, 0x69, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1e, 0x2e,
+	0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x6e,
+	0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x69, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x46, 0x69, 0x6e,
+	0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x0d, 0x5a,
+	0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
@@ -198,22 +1218,54 @@ func file_protobuf_id_generator_proto_rawDescGZIP() []byte {
 	return file_protobuf_id_generator_proto_rawDescData
 }
 
-var file_protobuf_id_generator_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_protobuf_id_generator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_protobuf_id_generator_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_protobuf_id_generator_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_protobuf_id_generator_proto_goTypes = []any{
-	(SysType)(0),            // 0: id_generator.SysType
-	(*UniqueIdReply)(nil),   // 1: id_generator.UniqueIdReply
-	(*UniqueIdRequest)(nil), // 2: id_generator.UniqueIdRequest
+	(SysType)(0),                // 0: id_generator.SysType
+	(IdFormat)(0),               // 1: id_generator.IdFormat
+	(*UniqueIdReply)(nil),       // 2: id_generator.UniqueIdReply
+	(*UniqueIdRequest)(nil),     // 3: id_generator.UniqueIdRequest
+	(*UniqueIdsReply)(nil),      // 4: id_generator.UniqueIdsReply
+	(*UniqueIdsRequest)(nil),    // 5: id_generator.UniqueIdsRequest
+	(*DecodeIdReply)(nil),       // 6: id_generator.DecodeIdReply
+	(*DecodeIdRequest)(nil),     // 7: id_generator.DecodeIdRequest
+	(*SysTypeInfo)(nil),         // 8: id_generator.SysTypeInfo
+	(*ListSysTypesReply)(nil),   // 9: id_generator.ListSysTypesReply
+	(*ListSysTypesRequest)(nil), // 10: id_generator.ListSysTypesRequest
+	(*ValidateIdReply)(nil),     // 11: id_generator.ValidateIdReply
+	(*ValidateIdRequest)(nil),   // 12: id_generator.ValidateIdRequest
+	(*LedgerEntry)(nil),         // 13: id_generator.LedgerEntry
+	(*FindBlockReply)(nil),      // 14: id_generator.FindBlockReply
+	(*FindBlockRequest)(nil),    // 15: id_generator.FindBlockRequest
 }
 var file_protobuf_id_generator_proto_depIdxs = []int32{
-	0, // 0: id_generator.UniqueIdRequest.sys_type:type_name -> id_generator.SysType
-	2, // 1: id_generator.Generator.GetUniqueId:input_type -> id_generator.UniqueIdRequest
-	1, // 2: id_generator.Generator.GetUniqueId:output_type -> id_generator.UniqueIdReply
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: id_generator.UniqueIdRequest.sys_type:type_name -> id_generator.SysType
+	1,  // 1: id_generator.UniqueIdRequest.format:type_name -> id_generator.IdFormat
+	0,  // 2: id_generator.UniqueIdsRequest.sys_type:type_name -> id_generator.SysType
+	1,  // 3: id_generator.UniqueIdsRequest.format:type_name -> id_generator.IdFormat
+	0,  // 4: id_generator.DecodeIdReply.sys_type:type_name -> id_generator.SysType
+	1,  // 5: id_generator.DecodeIdRequest.format:type_name -> id_generator.IdFormat
+	8,  // 6: id_generator.ListSysTypesReply.sys_types:type_name -> id_generator.SysTypeInfo
+	1,  // 7: id_generator.ValidateIdRequest.format:type_name -> id_generator.IdFormat
+	13, // 8: id_generator.FindBlockReply.entries:type_name -> id_generator.LedgerEntry
+	1,  // 9: id_generator.FindBlockRequest.format:type_name -> id_generator.IdFormat
+	3,  // 10: id_generator.Generator.GetUniqueId:input_type -> id_generator.UniqueIdRequest
+	5,  // 11: id_generator.Generator.GetUniqueIds:input_type -> id_generator.UniqueIdsRequest
+	7,  // 12: id_generator.Generator.DecodeId:input_type -> id_generator.DecodeIdRequest
+	10, // 13: id_generator.Generator.ListSysTypes:input_type -> id_generator.ListSysTypesRequest
+	12, // 14: id_generator.Generator.ValidateId:input_type -> id_generator.ValidateIdRequest
+	15, // 15: id_generator.Generator.FindBlock:input_type -> id_generator.FindBlockRequest
+	2,  // 16: id_generator.Generator.GetUniqueId:output_type -> id_generator.UniqueIdReply
+	4,  // 17: id_generator.Generator.GetUniqueIds:output_type -> id_generator.UniqueIdsReply
+	6,  // 18: id_generator.Generator.DecodeId:output_type -> id_generator.DecodeIdReply
+	9,  // 19: id_generator.Generator.ListSysTypes:output_type -> id_generator.ListSysTypesReply
+	11, // 20: id_generator.Generator.ValidateId:output_type -> id_generator.ValidateIdReply
+	14, // 21: id_generator.Generator.FindBlock:output_type -> id_generator.FindBlockReply
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_id_generator_proto_init() }
@@ -226,8 +1278,8 @@ func file_protobuf_id_generator_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protobuf_id_generator_proto_rawDesc), len(file_protobuf_id_generator_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Generator_GetUniqueId_FullMethodName = "/id_generator.Generator/GetUniqueId"
+	Generator_GetUniqueId_FullMethodName  = "/id_generator.Generator/GetUniqueId"
+	Generator_GetUniqueIds_FullMethodName = "/id_generator.Generator/GetUniqueIds"
+	Generator_DecodeId_FullMethodName     = "/id_generator.Generator/DecodeId"
+	Generator_ListSysTypes_FullMethodName = "/id_generator.Generator/ListSysTypes"
+	Generator_ValidateId_FullMethodName   = "/id_generator.Generator/ValidateId"
+	Generator_FindBlock_FullMethodName    = "/id_generator.Generator/FindBlock"
 )
 
 // GeneratorClient is the client API for Generator service.
@@ -27,6 +32,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GeneratorClient interface {
 	GetUniqueId(ctx context.Context, in *UniqueIdRequest, opts ...grpc.CallOption) (*UniqueIdReply, error)
+	GetUniqueIds(ctx context.Context, in *UniqueIdsRequest, opts ...grpc.CallOption) (*UniqueIdsReply, error)
+	DecodeId(ctx context.Context, in *DecodeIdRequest, opts ...grpc.CallOption) (*DecodeIdReply, error)
+	ListSysTypes(ctx context.Context, in *ListSysTypesRequest, opts ...grpc.CallOption) (*ListSysTypesReply, error)
+	ValidateId(ctx context.Context, in *ValidateIdRequest, opts ...grpc.CallOption) (*ValidateIdReply, error)
+	// finds the block of the id and nodes it was given out to in the allocation ledger
+	FindBlock(ctx context.Context, in *FindBlockRequest, opts ...grpc.CallOption) (*FindBlockReply, error)
 }
 
 type generatorClient struct {
@@ -47,11 +58,67 @@ func (c *generatorClient) GetUniqueId(ctx context.Context, in *UniqueIdRequest,
 	return out, nil
 }
 
+func (c *generatorClient) GetUniqueIds(ctx context.Context, in *UniqueIdsRequest, opts ...grpc.CallOption) (*UniqueIdsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UniqueIdsReply)
+	err := c.cc.Invoke(ctx, Generator_GetUniqueIds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) DecodeId(ctx context.Context, in *DecodeIdRequest, opts ...grpc.CallOption) (*DecodeIdReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecodeIdReply)
+	err := c.cc.Invoke(ctx, Generator_DecodeId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) ListSysTypes(ctx context.Context, in *ListSysTypesRequest, opts ...grpc.CallOption) (*ListSysTypesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSysTypesReply)
+	err := c.cc.Invoke(ctx, Generator_ListSysTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) ValidateId(ctx context.Context, in *ValidateIdRequest, opts ...grpc.CallOption) (*ValidateIdReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateIdReply)
+	err := c.cc.Invoke(ctx, Generator_ValidateId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorClient) FindBlock(ctx context.Context, in *FindBlockRequest, opts ...grpc.CallOption) (*FindBlockReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindBlockReply)
+	err := c.cc.Invoke(ctx, Generator_FindBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GeneratorServer is the server API for Generator service.
 // All implementations must embed UnimplementedGeneratorServer
 // for forward compatibility.
 type GeneratorServer interface {
 	GetUniqueId(context.Context, *UniqueIdRequest) (*UniqueIdReply, error)
+	GetUniqueIds(context.Context, *UniqueIdsRequest) (*UniqueIdsReply, error)
+	DecodeId(context.Context, *DecodeIdRequest) (*DecodeIdReply, error)
+	ListSysTypes(context.Context, *ListSysTypesRequest) (*ListSysTypesReply, error)
+	ValidateId(context.Context, *ValidateIdRequest) (*ValidateIdReply, error)
+	// finds the block of the id and nodes it was given out to in the allocation ledger
+	FindBlock(context.Context, *FindBlockRequest) (*FindBlockReply, error)
 	mustEmbedUnimplementedGeneratorServer()
 }
 
@@ -65,6 +132,21 @@ type UnimplementedGeneratorServer struct{}
 func (UnimplementedGeneratorServer) GetUniqueId(context.Context, *UniqueIdRequest) (*UniqueIdReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUniqueId not implemented")
 }
+func (UnimplementedGeneratorServer) GetUniqueIds(context.Context, *UniqueIdsRequest) (*UniqueIdsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUniqueIds not implemented")
+}
+func (UnimplementedGeneratorServer) DecodeId(context.Context, *DecodeIdRequest) (*DecodeIdReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodeId not implemented")
+}
+func (UnimplementedGeneratorServer) ListSysTypes(context.Context, *ListSysTypesRequest) (*ListSysTypesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSysTypes not implemented")
+}
+func (UnimplementedGeneratorServer) ValidateId(context.Context, *ValidateIdRequest) (*ValidateIdReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateId not implemented")
+}
+func (UnimplementedGeneratorServer) FindBlock(context.Context, *FindBlockRequest) (*FindBlockReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindBlock not implemented")
+}
 func (UnimplementedGeneratorServer) mustEmbedUnimplementedGeneratorServer() {}
 func (UnimplementedGeneratorServer) testEmbeddedByValue()                   {}
 
@@ -104,6 +186,96 @@ func _Generator_GetUniqueId_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Generator_GetUniqueIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UniqueIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).GetUniqueIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_GetUniqueIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).GetUniqueIds(ctx, req.(*UniqueIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_DecodeId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).DecodeId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_DecodeId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).DecodeId(ctx, req.(*DecodeIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_ListSysTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSysTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).ListSysTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_ListSysTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).ListSysTypes(ctx, req.(*ListSysTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_ValidateId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).ValidateId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_ValidateId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).ValidateId(ctx, req.(*ValidateIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Generator_FindBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorServer).FindBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Generator_FindBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorServer).FindBlock(ctx, req.(*FindBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Generator_ServiceDesc is the grpc.ServiceDesc for Generator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +287,26 @@ var Generator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUniqueId",
 			Handler:    _Generator_GetUniqueId_Handler,
 		},
+		{
+			MethodName: "GetUniqueIds",
+			Handler:    _Generator_GetUniqueIds_Handler,
+		},
+		{
+			MethodName: "DecodeId",
+			Handler:    _Generator_DecodeId_Handler,
+		},
+		{
+			MethodName: "ListSysTypes",
+			Handler:    _Generator_ListSysTypes_Handler,
+		},
+		{
+			MethodName: "ValidateId",
+			Handler:    _Generator_ValidateId_Handler,
+		},
+		{
+			MethodName: "FindBlock",
+			Handler:    _Generator_FindBlock_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/id-generator.proto",
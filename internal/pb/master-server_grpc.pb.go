@@ -20,6 +20,9 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	Orchestrator_GetMultiplierAndTimestamp_FullMethodName = "/id_generator.Orchestrator/GetMultiplierAndTimestamp"
+	Orchestrator_AcquireWorkerId_FullMethodName           = "/id_generator.Orchestrator/AcquireWorkerId"
+	Orchestrator_RenewWorkerId_FullMethodName             = "/id_generator.Orchestrator/RenewWorkerId"
+	Orchestrator_ReleaseWorkerId_FullMethodName           = "/id_generator.Orchestrator/ReleaseWorkerId"
 )
 
 // OrchestratorClient is the client API for Orchestrator service.
@@ -27,6 +30,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrchestratorClient interface {
 	GetMultiplierAndTimestamp(ctx context.Context, in *MultiplierAndTimestampRequest, opts ...grpc.CallOption) (*MultiplierAndTimestampReply, error)
+	// worker ids of generator nodes in snowflake mode, see README
+	AcquireWorkerId(ctx context.Context, in *AcquireWorkerIdRequest, opts ...grpc.CallOption) (*WorkerIdLease, error)
+	RenewWorkerId(ctx context.Context, in *WorkerIdRequest, opts ...grpc.CallOption) (*WorkerIdLease, error)
+	ReleaseWorkerId(ctx context.Context, in *WorkerIdRequest, opts ...grpc.CallOption) (*ReleaseWorkerIdReply, error)
 }
 
 type orchestratorClient struct {
@@ -47,11 +54,45 @@ func (c *orchestratorClient) GetMultiplierAndTimestamp(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *orchestratorClient) AcquireWorkerId(ctx context.Context, in *AcquireWorkerIdRequest, opts ...grpc.CallOption) (*WorkerIdLease, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkerIdLease)
+	err := c.cc.Invoke(ctx, Orchestrator_AcquireWorkerId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) RenewWorkerId(ctx context.Context, in *WorkerIdRequest, opts ...grpc.CallOption) (*WorkerIdLease, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkerIdLease)
+	err := c.cc.Invoke(ctx, Orchestrator_RenewWorkerId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorClient) ReleaseWorkerId(ctx context.Context, in *WorkerIdRequest, opts ...grpc.CallOption) (*ReleaseWorkerIdReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseWorkerIdReply)
+	err := c.cc.Invoke(ctx, Orchestrator_ReleaseWorkerId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServer is the server API for Orchestrator service.
 // All implementations must embed UnimplementedOrchestratorServer
 // for forward compatibility.
 type OrchestratorServer interface {
 	GetMultiplierAndTimestamp(context.Context, *MultiplierAndTimestampRequest) (*MultiplierAndTimestampReply, error)
+	// worker ids of generator nodes in snowflake mode, see README
+	AcquireWorkerId(context.Context, *AcquireWorkerIdRequest) (*WorkerIdLease, error)
+	RenewWorkerId(context.Context, *WorkerIdRequest) (*WorkerIdLease, error)
+	ReleaseWorkerId(context.Context, *WorkerIdRequest) (*ReleaseWorkerIdReply, error)
 	mustEmbedUnimplementedOrchestratorServer()
 }
 
@@ -65,6 +106,15 @@ type UnimplementedOrchestratorServer struct{}
 func (UnimplementedOrchestratorServer) GetMultiplierAndTimestamp(context.Context, *MultiplierAndTimestampRequest) (*MultiplierAndTimestampReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMultiplierAndTimestamp not implemented")
 }
+func (UnimplementedOrchestratorServer) AcquireWorkerId(context.Context, *AcquireWorkerIdRequest) (*WorkerIdLease, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquireWorkerId not implemented")
+}
+func (UnimplementedOrchestratorServer) RenewWorkerId(context.Context, *WorkerIdRequest) (*WorkerIdLease, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewWorkerId not implemented")
+}
+func (UnimplementedOrchestratorServer) ReleaseWorkerId(context.Context, *WorkerIdRequest) (*ReleaseWorkerIdReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseWorkerId not implemented")
+}
 func (UnimplementedOrchestratorServer) mustEmbedUnimplementedOrchestratorServer() {}
 func (UnimplementedOrchestratorServer) testEmbeddedByValue()                      {}
 
@@ -104,6 +154,60 @@ func _Orchestrator_GetMultiplierAndTimestamp_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orchestrator_AcquireWorkerId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireWorkerIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).AcquireWorkerId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_AcquireWorkerId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).AcquireWorkerId(ctx, req.(*AcquireWorkerIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_RenewWorkerId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).RenewWorkerId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_RenewWorkerId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).RenewWorkerId(ctx, req.(*WorkerIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orchestrator_ReleaseWorkerId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServer).ReleaseWorkerId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Orchestrator_ReleaseWorkerId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServer).ReleaseWorkerId(ctx, req.(*WorkerIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Orchestrator_ServiceDesc is the grpc.ServiceDesc for Orchestrator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +219,18 @@ var Orchestrator_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMultiplierAndTimestamp",
 			Handler:    _Orchestrator_GetMultiplierAndTimestamp_Handler,
 		},
+		{
+			MethodName: "AcquireWorkerId",
+			Handler:    _Orchestrator_AcquireWorkerId_Handler,
+		},
+		{
+			MethodName: "RenewWorkerId",
+			Handler:    _Orchestrator_RenewWorkerId_Handler,
+		},
+		{
+			MethodName: "ReleaseWorkerId",
+			Handler:    _Orchestrator_ReleaseWorkerId_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/master-server.proto",
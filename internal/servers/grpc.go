@@ -2,31 +2,51 @@ package servers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 
 	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/internal/ledger"
+	"id-generator/internal/metrics"
 	"id-generator/internal/pb"
+	"id-generator/pkg/idformat"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 type grpcServer struct {
-	Port    int
-	Storage *generator_storage.Storage
-	server  *grpc.Server
+	Port     int
+	Registry *generator_storage.Registry
+	// Ledger is nil if the allocation ledger is disabled.
+	Ledger ledger.Ledger
+	// Reflection enables server reflection, so tools like grpcurl can list services.
+	Reflection bool
+	server     *grpc.Server
+	health     *health.Server
+	stopHealth context.CancelFunc
 }
 
 type grpcController struct {
 	pb.UnimplementedGeneratorServer
-	storage *generator_storage.Storage
+	registry *generator_storage.Registry
+	ledger   ledger.Ledger
 }
 
-func NewGrpcServer(port int, storage *generator_storage.Storage) *grpcServer {
+func NewGrpcServer(port int, registry *generator_storage.Registry, allocationLedger ledger.Ledger, reflection bool) *grpcServer {
 	return &grpcServer{
-		Port:    port,
-		Storage: storage,
+		Port:       port,
+		Registry:   registry,
+		Ledger:     allocationLedger,
+		Reflection: reflection,
 	}
 }
 
@@ -40,10 +60,27 @@ func (s *grpcServer) Serve() error {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(metrics.UnaryServerInterceptor()),
+	)
 	pb.RegisterGeneratorServer(grpcServer, &grpcController{
-		storage: s.Storage,
+		registry: s.Registry,
+		ledger:   s.Ledger,
 	})
+
+	// the node is reported as not serving while started storages can't issue ids right away
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	s.health, s.stopHealth = health.NewServer(), stopHealth
+	healthpb.RegisterHealthServer(grpcServer, s.health)
+	WatchHealth(healthCtx, s.health, func(context.Context) error {
+		return s.Registry.Health()
+	}, pb.Generator_ServiceDesc.ServiceName)
+
+	if s.Reflection {
+		reflection.Register(grpcServer)
+	}
+
 	log.Printf("grpc server listening at %v", lis.Addr())
 	s.server = grpcServer
 
@@ -63,14 +100,230 @@ func (s *grpcServer) Stop(stopCh chan struct{}, done func()) {
 		panic("can't stop non-exist grpc server")
 	}
 
+	// load balancers stop sending requests, while in-flight ones are finished
+	s.stopHealth()
+	s.health.Shutdown()
 	s.server.GracefulStop()
 }
 
-func (s *grpcController) GetUniqueId(_ context.Context, req *pb.UniqueIdRequest) (*pb.UniqueIdReply, error) {
-	newId, err := s.storage.GetUniqueIdWithType(req.GetSysType().String())
+func (s *grpcController) GetUniqueId(ctx context.Context, req *pb.UniqueIdRequest) (*pb.UniqueIdReply, error) {
+	storage, err := s.storage(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := idOptions(req.GetBoxKey(), req.GetEncoding(), req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetFormat() == pb.IdFormat_INT64 {
+		newId, err := storage.GetUniqueNumericIdWithType(ctx, sysTypeName(req.GetSysType(), req.GetSysTypeName()), opts)
+		if err != nil {
+			return nil, toGrpcError(fmt.Errorf("error while generating new unique id: %w", err))
+		}
+
+		return &pb.UniqueIdReply{Id: strconv.FormatInt(newId, 10), NumericId: newId}, nil
+	}
+
+	newId, err := storage.GetUniqueIdWithType(ctx, sysTypeName(req.GetSysType(), req.GetSysTypeName()), opts)
 	if err != nil {
-		return nil, fmt.Errorf("error while generating new unique id: %v", err)
+		return nil, toGrpcError(fmt.Errorf("error while generating new unique id: %w", err))
 	}
 
 	return &pb.UniqueIdReply{Id: newId}, nil
 }
+
+func (s *grpcController) GetUniqueIds(ctx context.Context, req *pb.UniqueIdsRequest) (*pb.UniqueIdsReply, error) {
+	storage, err := s.storage(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := idOptions(req.GetBoxKey(), req.GetEncoding(), req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetFormat() == pb.IdFormat_INT64 {
+		newIds, err := storage.GetUniqueNumericIdsWithType(
+			ctx, sysTypeName(req.GetSysType(), req.GetSysTypeName()), opts, int(req.GetCount()),
+		)
+		if err != nil {
+			return nil, toGrpcError(fmt.Errorf("error while generating new unique ids: %w", err))
+		}
+
+		return &pb.UniqueIdsReply{Ids: formatNumericIds(newIds), NumericIds: newIds}, nil
+	}
+
+	newIds, err := storage.GetUniqueIdsWithType(ctx, sysTypeName(req.GetSysType(), req.GetSysTypeName()), opts, int(req.GetCount()))
+	if err != nil {
+		return nil, toGrpcError(fmt.Errorf("error while generating new unique ids: %w", err))
+	}
+
+	return &pb.UniqueIdsReply{Ids: newIds}, nil
+}
+
+func toGrpcError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.Error(status.FromContextError(err).Code(), err.Error())
+	}
+
+	var unavailableErr *generator_storage.UnavailableError
+	if errors.As(err, &unavailableErr) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	var invalidRequestErr *generator_storage.InvalidRequestError
+	if errors.As(err, &invalidRequestErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return err
+}
+
+func (s *grpcController) DecodeId(_ context.Context, req *pb.DecodeIdRequest) (*pb.DecodeIdReply, error) {
+	opts, err := idOptions("", req.GetEncoding(), req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, decodedId, err := s.registry.DecodeId(req.GetId(), opts)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error while decoding id: %v", err)
+	}
+
+	return &pb.DecodeIdReply{
+		Timestamp:    decodedId.Timestamp,
+		SysType:      pb.SysType(pb.SysType_value[decodedId.SysType]),
+		SysTypeDigit: int32(decodedId.SysTypeDigit),
+		Multiplier:   decodedId.Multiplier,
+		Offset:       decodedId.Offset,
+		SysTypeName:  decodedId.SysType,
+		Namespace:    namespace,
+	}, nil
+}
+
+func (s *grpcController) ListSysTypes(_ context.Context, req *pb.ListSysTypesRequest) (*pb.ListSysTypesReply, error) {
+	storage, err := s.storage(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	sysTypes := storage.SysTypes().List()
+
+	reply := &pb.ListSysTypesReply{SysTypes: make([]*pb.SysTypeInfo, len(sysTypes))}
+	for i, sysType := range sysTypes {
+		reply.SysTypes[i] = &pb.SysTypeInfo{
+			Name:     sysType.Name,
+			MinDigit: int32(sysType.MinDigit),
+			MaxDigit: int32(sysType.MaxDigit),
+		}
+	}
+
+	return reply, nil
+}
+
+// ValidateId reports whether the id is valid instead of returning an error, malformed ids are expected here.
+func (s *grpcController) ValidateId(_ context.Context, req *pb.ValidateIdRequest) (*pb.ValidateIdReply, error) {
+	opts, err := idOptions("", req.GetEncoding(), req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, err := s.registry.ValidateId(req.GetId(), opts)
+	if err != nil {
+		return &pb.ValidateIdReply{Valid: false, Reason: err.Error()}, nil
+	}
+
+	return &pb.ValidateIdReply{Valid: true, Namespace: namespace}, nil
+}
+
+// FindBlock decodes the id and looks for its block in the allocation ledger. Without the ledger it returns FailedPrecondition code.
+func (s *grpcController) FindBlock(ctx context.Context, req *pb.FindBlockRequest) (*pb.FindBlockReply, error) {
+	if s.ledger == nil {
+		return nil, status.Error(codes.FailedPrecondition, "allocation ledger is disabled")
+	}
+
+	opts, err := idOptions("", req.GetEncoding(), req.GetFormat())
+	if err != nil {
+		return nil, err
+	}
+
+	block, around, err := s.registry.BlockOfId(req.GetId(), opts)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error while decoding id: %v", err)
+	}
+
+	entries, err := s.ledger.Find(ctx, block, around)
+	if err != nil {
+		return nil, toGrpcError(fmt.Errorf("error while reading allocation ledger: %w", err))
+	}
+
+	reply := &pb.FindBlockReply{
+		Namespace:        block.Namespace,
+		CounterNamespace: block.CounterNamespace,
+		Multiplier:       block.Multiplier,
+		Timestamp:        block.Timestamp,
+		Entries:          make([]*pb.LedgerEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		reply.Entries[i] = &pb.LedgerEntry{Event: entry.Event, Node: entry.Node, AtMs: entry.At.UnixMilli()}
+	}
+
+	return reply, nil
+}
+
+// storage returns storage of the namespace, unknown namespaces get NotFound code.
+func (s *grpcController) storage(namespace string) (*generator_storage.Storage, error) {
+	storage, err := s.registry.Storage(namespace)
+	if errors.Is(err, generator_storage.ErrUnknownNamespace) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return storage, err
+}
+
+// idOptions returns options of requested ids, invalid ones get InvalidArgument code.
+func idOptions(boxKey, encodingName string, format pb.IdFormat) (generator_storage.IdOptions, error) {
+	encoding, err := idformat.ParseEncoding(encodingName)
+	if err != nil {
+		return generator_storage.IdOptions{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if encoding != nil && format != pb.IdFormat_DECIMAL {
+		return generator_storage.IdOptions{}, status.Errorf(codes.InvalidArgument, "%s ids can't be encoded with %s", format, encodingName)
+	}
+
+	return generator_storage.IdOptions{ShardKey: boxKey, Encoding: encoding, Format: stringFormat(format)}, nil
+}
+
+// stringFormat returns format of string ids, int64 ids are packed from decimal ones.
+func stringFormat(format pb.IdFormat) generator_storage.Format {
+	switch format {
+	case pb.IdFormat_UUID_V7:
+		return generator_storage.FormatUUIDv7
+	case pb.IdFormat_ULID:
+		return generator_storage.FormatULID
+	}
+
+	return generator_storage.FormatDecimal
+}
+
+// sysTypeName returns name of sys type from the registry if it is set, otherwise name of the enum.
+func sysTypeName(sysType pb.SysType, name string) string {
+	if name != "" {
+		return name
+	}
+
+	return sysType.String()
+}
+
+func formatNumericIds(ids []int64) []string {
+	formattedIds := make([]string, len(ids))
+	for i, id := range ids {
+		formattedIds[i] = strconv.FormatInt(id, 10)
+	}
+
+	return formattedIds
+}
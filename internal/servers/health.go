@@ -0,0 +1,64 @@
+package servers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval is how often serving status of the health service is updated.
+const healthCheckInterval = time.Second
+
+// WatchHealth sets serving status of the server as a whole and of the services to NOT_SERVING while the check
+// fails and to SERVING otherwise, until ctx is done. The first check is done before it returns, so the server
+// isn't reported as serving before it is checked. Changes of the status are logged.
+func WatchHealth(ctx context.Context, healthServer *health.Server, check func(ctx context.Context) error, services ...string) {
+	services = append([]string{""}, services...)
+
+	var lastStatus healthpb.HealthCheckResponse_ServingStatus
+
+	update := func() {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckInterval)
+		defer cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		err := check(checkCtx)
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		if status == lastStatus {
+			return
+		}
+
+		if err != nil {
+			log.Printf("grpc health status is %s: %v", status, err)
+		} else {
+			log.Printf("grpc health status is %s", status)
+		}
+
+		for _, service := range services {
+			healthServer.SetServingStatus(service, status)
+		}
+		lastStatus = status
+	}
+
+	update()
+
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				update()
+			}
+		}
+	}()
+}
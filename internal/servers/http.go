@@ -2,27 +2,41 @@ package servers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	generator_storage "id-generator/internal/generator-storage"
+	"id-generator/internal/ledger"
+	"id-generator/internal/metrics"
+	"id-generator/internal/tracing"
+	"id-generator/pkg/idformat"
 )
 
 type httpServer struct {
-	Port    int
-	Storage *generator_storage.Storage
-	server  *http.Server
+	Port     int
+	Registry *generator_storage.Registry
+	// Ledger is nil if the allocation ledger is disabled.
+	Ledger ledger.Ledger
+	server *http.Server
 }
 
 type httpController struct {
-	storage *generator_storage.Storage
+	registry *generator_storage.Registry
+	ledger   ledger.Ledger
 }
 
-func NewHttpServer(port int, storage *generator_storage.Storage) *httpServer {
+func NewHttpServer(port int, registry *generator_storage.Registry, allocationLedger ledger.Ledger) *httpServer {
 	return &httpServer{
-		Port:    port,
-		Storage: storage,
+		Port:     port,
+		Registry: registry,
+		Ledger:   allocationLedger,
 	}
 }
 
@@ -62,22 +76,57 @@ func (s *httpServer) Stop(stopCh chan struct{}, done func()) {
 
 func (s *httpServer) getHandler() http.Handler {
 	httpController := &httpController{
-		storage: s.Storage,
+		registry: s.Registry,
+		ledger:   s.Ledger,
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/get-unique-id", httpController.getUniqueId)
+	mux.HandleFunc("/decode-id", httpController.decodeId)
+	mux.HandleFunc("/validate-id", httpController.validateId)
+	mux.HandleFunc("/stats", httpController.stats)
+	mux.HandleFunc("/find-block", httpController.findBlock)
+	mux.Handle("/metrics", metrics.Handler())
+	// routes of named namespaces, routes above serve the default one
+	mux.HandleFunc("/{namespace}/get-unique-id", httpController.getUniqueId)
+	mux.HandleFunc("/{namespace}/stats", httpController.stats)
 
-	return mux
+	return tracing.HTTPMiddleware(metrics.HTTPMiddleware(mux))
 }
 
 func (s *httpController) getUniqueId(res http.ResponseWriter, req *http.Request) {
+	storage, ok := s.storage(res, req)
+	if !ok {
+		return
+	}
+
 	query := req.URL.Query()
 	sysType := query.Get("sys_type")
 
-	newId, err := s.storage.GetUniqueIdWithType(sysType)
+	ctx, cancel, err := requestContext(req)
 	if err != nil {
-		res.WriteHeader(http.StatusInternalServerError)
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(err.Error()))
+		return
+	}
+	defer cancel()
+
+	format := query.Get("format")
+	opts, err := idOptionsOfQuery(query)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(err.Error()))
+		return
+	}
+
+	if query.Has("count") {
+		s.getUniqueIds(ctx, res, storage, sysType, opts, format, query.Get("count"))
+		return
+	}
+
+	newId, err := generateId(ctx, storage, sysType, opts, format)
+	if err != nil {
+		res.WriteHeader(toHttpStatus(err))
 		res.Write([]byte(fmt.Sprintf("error while generating new unique id: %v", err)))
 		return
 	}
@@ -85,3 +134,260 @@ func (s *httpController) getUniqueId(res http.ResponseWriter, req *http.Request)
 	res.WriteHeader(http.StatusOK)
 	res.Write([]byte(newId))
 }
+
+// getUniqueIds writes requested number of ids separated by new line.
+func (s *httpController) getUniqueIds(
+	ctx context.Context, res http.ResponseWriter, storage *generator_storage.Storage, sysType string, opts generator_storage.IdOptions,
+	format, countStr string,
+) {
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(fmt.Sprintf("count must be a number: %s", countStr)))
+		return
+	}
+
+	newIds, err := generateIds(ctx, storage, sysType, opts, format, count)
+	if err != nil {
+		res.WriteHeader(toHttpStatus(err))
+		res.Write([]byte(fmt.Sprintf("error while generating new unique ids: %v", err)))
+		return
+	}
+
+	res.WriteHeader(http.StatusOK)
+	res.Write([]byte(strings.Join(newIds, "\n")))
+}
+
+func generateId(
+	ctx context.Context, storage *generator_storage.Storage, sysType string, opts generator_storage.IdOptions, format string,
+) (string, error) {
+	if format == "int" {
+		newId, err := storage.GetUniqueNumericIdWithType(ctx, sysType, opts)
+		return strconv.FormatInt(newId, 10), err
+	}
+
+	return storage.GetUniqueIdWithType(ctx, sysType, opts)
+}
+
+func generateIds(
+	ctx context.Context, storage *generator_storage.Storage, sysType string, opts generator_storage.IdOptions, format string, count int,
+) ([]string, error) {
+	if format == "int" {
+		newIds, err := storage.GetUniqueNumericIdsWithType(ctx, sysType, opts, count)
+		return formatNumericIds(newIds), err
+	}
+
+	return storage.GetUniqueIdsWithType(ctx, sysType, opts, count)
+}
+
+func (s *httpController) decodeId(res http.ResponseWriter, req *http.Request) {
+	opts, err := idOptionsOfQuery(req.URL.Query())
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(err.Error()))
+		return
+	}
+
+	namespace, decodedId, err := s.registry.DecodeId(req.URL.Query().Get("id"), opts)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(fmt.Sprintf("error while decoding id: %v", err)))
+		return
+	}
+
+	body, err := json.Marshal(struct {
+		Namespace string `json:"namespace,omitempty"`
+		idformat.ID
+	}{namespace, decodedId})
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		res.Write([]byte(fmt.Sprintf("error while encoding decoded id: %v", err)))
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// validateId writes JSON with validity of the id, invalid ids get the reason.
+func (s *httpController) validateId(res http.ResponseWriter, req *http.Request) {
+	reply := struct {
+		Valid     bool   `json:"valid"`
+		Reason    string `json:"reason,omitempty"`
+		Namespace string `json:"namespace,omitempty"`
+	}{Valid: true}
+
+	opts, err := idOptionsOfQuery(req.URL.Query())
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(err.Error()))
+		return
+	}
+
+	namespace, err := s.registry.ValidateId(req.URL.Query().Get("id"), opts)
+	if err != nil {
+		reply.Valid, reply.Reason = false, err.Error()
+	}
+	reply.Namespace = namespace
+
+	body, err := json.Marshal(reply)
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		res.Write([]byte(fmt.Sprintf("error while encoding validation result: %v", err)))
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// findBlock writes JSON with the block of the id and entries of it in the allocation ledger.
+// Without the ledger it responds with 501 Not Implemented.
+func (s *httpController) findBlock(res http.ResponseWriter, req *http.Request) {
+	if s.ledger == nil {
+		res.WriteHeader(http.StatusNotImplemented)
+		res.Write([]byte("allocation ledger is disabled"))
+		return
+	}
+
+	opts, err := idOptionsOfQuery(req.URL.Query())
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(err.Error()))
+		return
+	}
+
+	block, around, err := s.registry.BlockOfId(req.URL.Query().Get("id"), opts)
+	if err != nil {
+		res.WriteHeader(http.StatusBadRequest)
+		res.Write([]byte(fmt.Sprintf("error while decoding id: %v", err)))
+		return
+	}
+
+	entries, err := s.ledger.Find(req.Context(), block, around)
+	if err != nil {
+		res.WriteHeader(toHttpStatus(err))
+		res.Write([]byte(fmt.Sprintf("error while reading allocation ledger: %v", err)))
+		return
+	}
+
+	if entries == nil {
+		entries = []ledger.Entry{}
+	}
+
+	body, err := json.Marshal(struct {
+		generator_storage.Block
+		Entries []ledger.Entry `json:"entries"`
+	}{block, entries})
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		res.Write([]byte(fmt.Sprintf("error while encoding block: %v", err)))
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// stats writes stats of buffers of sys types as JSON.
+func (s *httpController) stats(res http.ResponseWriter, req *http.Request) {
+	storage, ok := s.storage(res, req)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(storage.Stats())
+	if err != nil {
+		res.WriteHeader(http.StatusInternalServerError)
+		res.Write([]byte(fmt.Sprintf("error while encoding stats: %v", err)))
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(body)
+}
+
+// storage returns storage of namespace from the path of the request, requests without it get the default one.
+// Unknown namespaces get 404 Not Found.
+func (s *httpController) storage(res http.ResponseWriter, req *http.Request) (*generator_storage.Storage, bool) {
+	storage, err := s.registry.Storage(req.PathValue("namespace"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, generator_storage.ErrUnknownNamespace) {
+			status = http.StatusNotFound
+		}
+
+		res.WriteHeader(status)
+		res.Write([]byte(err.Error()))
+		return nil, false
+	}
+
+	return storage, true
+}
+
+// idOptionsOfQuery returns options of ids from box_key, encoding and format query parameters.
+// Format is decimal, int, uuid or ulid, only decimal ids can be encoded.
+func idOptionsOfQuery(query url.Values) (generator_storage.IdOptions, error) {
+	encoding, err := idformat.ParseEncoding(query.Get("encoding"))
+	if err != nil {
+		return generator_storage.IdOptions{}, err
+	}
+
+	opts := generator_storage.IdOptions{ShardKey: query.Get("box_key"), Encoding: encoding}
+
+	format := query.Get("format")
+	switch format {
+	case "", "decimal", "int":
+	case "uuid":
+		opts.Format = generator_storage.FormatUUIDv7
+	case "ulid":
+		opts.Format = generator_storage.FormatULID
+	default:
+		return generator_storage.IdOptions{}, fmt.Errorf("format must be decimal, int, uuid or ulid: %s", format)
+	}
+
+	if encoding != nil && format != "" && format != "decimal" {
+		return generator_storage.IdOptions{}, fmt.Errorf("%s ids can't be encoded with %s", format, encoding.Name())
+	}
+
+	return opts, nil
+}
+
+// requestContext returns context of the request limited by optional timeout query parameter, e.g. timeout=500ms.
+func requestContext(req *http.Request) (context.Context, context.CancelFunc, error) {
+	timeoutStr := req.URL.Query().Get("timeout")
+	if timeoutStr == "" {
+		ctx, cancel := context.WithCancel(req.Context())
+		return ctx, cancel, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("timeout must be a duration, e.g. 500ms: %s", timeoutStr)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+func toHttpStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return http.StatusGatewayTimeout
+	}
+
+	var unavailableErr *generator_storage.UnavailableError
+	if errors.As(err, &unavailableErr) {
+		return http.StatusServiceUnavailable
+	}
+
+	var invalidRequestErr *generator_storage.InvalidRequestError
+	if errors.As(err, &invalidRequestErr) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
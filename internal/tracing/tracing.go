@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Flags are command-line flags of tracing, shared by binaries.
+type Flags struct {
+	Exporter *string
+	File     *string
+}
+
+// RegisterFlags registers --trace-exporter and --trace-file flags.
+func RegisterFlags(fs *flag.FlagSet) Flags {
+	return Flags{
+		Exporter: fs.String("trace-exporter", "", "Exporter of OpenTelemetry spans: stdout or file, tracing is disabled if empty"),
+		File:     fs.String("trace-file", "traces.jsonl", "File of --trace-exporter=file, spans are written as JSON lines"),
+	}
+}
+
+// Init sets global tracer provider, which exports spans of the service with the exporter of flags, and
+// W3C trace context propagator, so spans of clients, generator nodes and master server make one trace.
+// Without exporter spans aren't recorded, but trace context is still propagated. The returned function
+// flushes spans and must be called on shutdown.
+func (f Flags) Init(serviceName string) (shutdown func(ctx context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		writer io.Writer
+		closer io.Closer
+	)
+
+	switch *f.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		writer = os.Stdout
+	case "file":
+		file, err := os.OpenFile(*f.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %v", err)
+		}
+
+		writer, closer = file, file
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %s", *f.Exporter)
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+
+		return err
+	}, nil
+}
+
+// HTTPMiddleware starts span of every request to the handler with trace context of the request headers.
+// Spans are named by patterns of http.ServeMux, so requests of named namespaces don't get a name each.
+func HTTPMiddleware(handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		handler.ServeHTTP(res, req)
+
+		switch {
+		case req.Pattern == "":
+		case strings.Contains(req.Pattern, " "):
+			// the pattern has the method already
+			trace.SpanFromContext(req.Context()).SetName(req.Pattern)
+		default:
+			trace.SpanFromContext(req.Context()).SetName(req.Method + " " + req.Pattern)
+		}
+	}), "http")
+}
+
+// RecordError records the error to the span and marks the span as failed.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{namespace}/get-unique-id", func(res http.ResponseWriter, req *http.Request) {
+		_, span := otel.Tracer("test").Start(req.Context(), "Storage.GetUniqueIdWithType")
+		RecordError(span, errors.New("buffer is empty"))
+		span.End()
+	})
+
+	handler := HTTPMiddleware(mux)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/get-unique-id", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	storageSpan, requestSpan := spans[0], spans[1]
+
+	if requestSpan.Name != "GET /{namespace}/get-unique-id" {
+		t.Errorf("expected span named by the route, got %q", requestSpan.Name)
+	}
+
+	if storageSpan.Parent.SpanID() != requestSpan.SpanContext.SpanID() {
+		t.Errorf("span of the handler isn't a child of span of the request")
+	}
+
+	if storageSpan.Status.Code != codes.Error || len(storageSpan.Events) != 1 {
+		t.Errorf("expected error to be recorded, got status %v and %d events", storageSpan.Status, len(storageSpan.Events))
+	}
+}
@@ -0,0 +1,159 @@
+package idformat
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CheckDigit is an algorithm of the check digit appended to decimal ids, so mistyped ids are rejected
+// instead of hitting wrong records. Luhn detects any single mistyped digit and most transpositions
+// of adjacent digits (all but 09 and 90), Damm and Verhoeff detect all of them.
+type CheckDigit struct {
+	name    string
+	compute func(digits []int) int
+}
+
+var checkDigits = []*CheckDigit{
+	{"luhn", luhn},
+	{"damm", damm},
+	{"verhoeff", verhoeff},
+}
+
+// ParseCheckDigit returns check digit algorithm by name: luhn, damm or verhoeff. Empty name returns nil.
+func ParseCheckDigit(name string) (*CheckDigit, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	index := slices.IndexFunc(checkDigits, func(checkDigit *CheckDigit) bool { return checkDigit.name == name })
+	if index == -1 {
+		return nil, fmt.Errorf("check digit must be luhn, damm or verhoeff, got %s", name)
+	}
+
+	return checkDigits[index], nil
+}
+
+func (c *CheckDigit) Name() string {
+	return c.name
+}
+
+// Append returns decimal id with its check digit.
+func (c *CheckDigit) Append(id string) (string, error) {
+	digits, err := toDigits(id)
+	if err != nil {
+		return "", err
+	}
+
+	return id + string(rune('0'+c.compute(digits))), nil
+}
+
+// Strip checks the last digit of id and returns id without it.
+func (c *CheckDigit) Strip(id string) (string, error) {
+	if len(id) < 2 {
+		return "", fmt.Errorf("id is too short to have a check digit")
+	}
+
+	body := id[:len(id)-1]
+
+	expected, err := c.Append(body)
+	if err != nil {
+		return "", err
+	}
+
+	if expected != id {
+		return "", fmt.Errorf("check digit of id doesn't match, the id is mistyped")
+	}
+
+	return body, nil
+}
+
+func toDigits(id string) ([]int, error) {
+	digits := make([]int, len(id))
+	for i, char := range id {
+		if char < '0' || char > '9' {
+			return nil, fmt.Errorf("id must consist of digits only, got %q", char)
+		}
+
+		digits[i] = int(char - '0')
+	}
+
+	return digits, nil
+}
+
+func luhn(digits []int) int {
+	sum := 0
+	for i := range digits {
+		digit := digits[len(digits)-1-i]
+		// digits are doubled starting from the rightmost one, because the check digit goes after it
+		if i%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+	}
+
+	return (10 - sum%10) % 10
+}
+
+// dammTable is a totally anti-symmetric quasigroup of order 10.
+var dammTable = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+func damm(digits []int) int {
+	interim := 0
+	for _, digit := range digits {
+		interim = dammTable[interim][digit]
+	}
+
+	return interim
+}
+
+var (
+	// verhoeffMultiplication is the multiplication table of the dihedral group D5.
+	verhoeffMultiplication = [10][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffPermutation = [8][10]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+	verhoeffInverse = [10]int{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+)
+
+func verhoeff(digits []int) int {
+	check := 0
+	for i := range digits {
+		// position 0 is taken by the check digit itself
+		check = verhoeffMultiplication[check][verhoeffPermutation[(i+1)%8][digits[len(digits)-1-i]]]
+	}
+
+	return verhoeffInverse[check]
+}
@@ -0,0 +1,82 @@
+package idformat
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock converts time to timestamps of ids: number of Resolution units passed since Epoch.
+type Clock struct {
+	Epoch      time.Time
+	Resolution time.Duration
+}
+
+// DefaultClock counts seconds since unix epoch.
+var DefaultClock = Clock{Epoch: time.Unix(0, 0).UTC(), Resolution: time.Second}
+
+// ParseClock converts values of ID_EPOCH (RFC 3339, e.g. 2025-01-01T00:00:00Z) and
+// ID_TIMESTAMP_RESOLUTION (s or ms) to the Clock. Empty values fall back to DefaultClock.
+func ParseClock(epochStr, resolutionStr string) (Clock, error) {
+	clock := DefaultClock
+
+	if epochStr != "" {
+		epoch, err := time.Parse(time.RFC3339, epochStr)
+		if err != nil {
+			return Clock{}, fmt.Errorf("failed to convert to RFC 3339 time ID_EPOCH")
+		}
+
+		clock.Epoch = epoch.UTC()
+	}
+
+	switch resolutionStr {
+	case "", "s":
+		clock.Resolution = time.Second
+	case "ms":
+		clock.Resolution = time.Millisecond
+	default:
+		return Clock{}, fmt.Errorf("ID_TIMESTAMP_RESOLUTION must be s or ms, got %s", resolutionStr)
+	}
+
+	return clock, clock.Validate()
+}
+
+func (c Clock) Validate() error {
+	if c.Resolution != time.Second && c.Resolution != time.Millisecond {
+		return fmt.Errorf("timestamp resolution must be 1s or 1ms, got %v", c.Resolution)
+	}
+
+	if c.Epoch.IsZero() || c.Epoch.Unix() < 0 {
+		return fmt.Errorf("epoch must not be before unix epoch")
+	}
+
+	if c.Epoch.After(time.Now()) {
+		return fmt.Errorf("epoch %v must not be in the future", c.Epoch)
+	}
+
+	return nil
+}
+
+// Now returns the current timestamp.
+func (c Clock) Now() int64 {
+	return c.Timestamp(time.Now())
+}
+
+// Timestamp returns number of Resolution units passed since Epoch till t.
+func (c Clock) Timestamp(t time.Time) int64 {
+	return int64(t.Sub(c.Epoch) / c.Resolution)
+}
+
+// Time returns start of the timestamp.
+func (c Clock) Time(timestamp int64) time.Time {
+	return c.Epoch.Add(time.Duration(timestamp) * c.Resolution)
+}
+
+// EpochUnits returns Epoch as number of Resolution units since unix epoch.
+func (c Clock) EpochUnits() int64 {
+	return c.Epoch.UnixMilli() / c.Resolution.Milliseconds()
+}
+
+// IsMillis reports whether timestamps are counted in milliseconds.
+func (c Clock) IsMillis() bool {
+	return c.Resolution == time.Millisecond
+}
@@ -0,0 +1,111 @@
+package idformat
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// Encoding encodes decimal ids with a shorter alphabet. Encoded ids have fixed width, which depends only
+// on the width of decimal ids, and characters of alphabets go in ASCII order, so encoded ids sort
+// the same way as decimal ones. Decoding returns the decimal id back.
+type Encoding struct {
+	name     string
+	alphabet string
+	// normalize converts alternative spellings of characters before decoding, e.g. lowercase ones.
+	normalize func(string) string
+}
+
+var encodings = []*Encoding{
+	// Crockford's base32 excludes I, L, O and U, decoding accepts lowercase and I, L, O as 1, 1, 0.
+	{"base32", "0123456789ABCDEFGHJKMNPQRSTVWXYZ", normalizeCrockford},
+	// base58 of Bitcoin excludes 0, O, I and l.
+	{"base58", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", nil},
+	{"base62", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", nil},
+}
+
+// ParseEncoding returns encoding by name: base32, base58 or base62. Empty name and decimal return nil.
+func ParseEncoding(name string) (*Encoding, error) {
+	if name == "" || name == "decimal" {
+		return nil, nil
+	}
+
+	index := slices.IndexFunc(encodings, func(encoding *Encoding) bool { return encoding.name == name })
+	if index == -1 {
+		return nil, fmt.Errorf("encoding must be decimal, base32, base58 or base62, got %s", name)
+	}
+
+	return encodings[index], nil
+}
+
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// Width returns number of characters of encoded ids of decimal width: the least n, that base^n >= 10^width.
+func (e *Encoding) Width(decimalWidth int) int {
+	base := big.NewInt(int64(len(e.alphabet)))
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimalWidth)), nil)
+
+	width := 0
+	for capacity := big.NewInt(1); capacity.Cmp(limit) < 0; capacity.Mul(capacity, base) {
+		width++
+	}
+
+	return width
+}
+
+// Encode returns decimal id in the encoding, padded with the first character of alphabet.
+func (e *Encoding) Encode(id string) (string, error) {
+	value, ok := new(big.Int).SetString(id, 10)
+	if !ok || value.Sign() < 0 {
+		return "", fmt.Errorf("id must consist of digits only: %s", id)
+	}
+
+	width := e.Width(len(id))
+	encoded := make([]byte, width)
+	base := big.NewInt(int64(len(e.alphabet)))
+	remainder := new(big.Int)
+
+	for i := width - 1; i >= 0; i-- {
+		value.QuoRem(value, base, remainder)
+		encoded[i] = e.alphabet[remainder.Int64()]
+	}
+
+	return string(encoded), nil
+}
+
+// Decode returns decimal id of decimal width back from the encoded one.
+func (e *Encoding) Decode(encoded string, decimalWidth int) (string, error) {
+	if e.normalize != nil {
+		encoded = e.normalize(encoded)
+	}
+
+	if width := e.Width(decimalWidth); len(encoded) != width {
+		return "", fmt.Errorf("%s id must consist of %d characters, got %d", e.name, width, len(encoded))
+	}
+
+	value := new(big.Int)
+	base := big.NewInt(int64(len(e.alphabet)))
+
+	for _, char := range encoded {
+		index := strings.IndexRune(e.alphabet, char)
+		if index == -1 {
+			return "", fmt.Errorf("%s id must not contain %q", e.name, char)
+		}
+
+		value.Mul(value, base).Add(value, big.NewInt(int64(index)))
+	}
+
+	id := value.String()
+	if len(id) > decimalWidth {
+		return "", fmt.Errorf("%s id is out of range of %d digits", e.name, decimalWidth)
+	}
+
+	return strings.Repeat("0", decimalWidth-len(id)) + id, nil
+}
+
+func normalizeCrockford(encoded string) string {
+	return strings.NewReplacer("I", "1", "L", "1", "O", "0").Replace(strings.ToUpper(encoded))
+}
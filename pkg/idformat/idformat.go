@@ -0,0 +1,376 @@
+// Package idformat formats and decodes ids issued by the generator.
+//
+// An id consists of timestamp (seconds or milliseconds since epoch of the Clock), sys type and the tail, each padded with zeros to the width of its Layout field
+// (10, 1 and 7 digits by default). The tail is an index of id within the timestamp:
+// (multiplier - 1) * block size + offset, where block size is 10^(tail digits) / MAX_ALLOWED_MULTIPLIER.
+package idformat
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTimestampDigits = 10
+	defaultSysTypeDigits   = 1
+
+	// maxTailDigits keeps tail within int32.
+	maxTailDigits    = 9
+	maxSysTypeDigits = 2
+)
+
+// ID is a decoded id.
+type ID struct {
+	Timestamp int64 `json:"timestamp"`
+	// Time is the time when the block of id was allocated.
+	Time         time.Time `json:"time"`
+	SysType      string    `json:"sys_type"`
+	SysTypeDigit int8      `json:"sys_type_digit"`
+	Multiplier   int32     `json:"multiplier"`
+	Offset       int32     `json:"offset"`
+}
+
+// Layout describes widths of id fields, the clock of timestamps and sys types. It must match configuration of the generator:
+// ID_TIMESTAMP_DIGITS, ID_SYS_TYPE_DIGITS, FREE_DIGITS_FOR_IDS, MAX_ALLOWED_MULTIPLIER, ID_EPOCH, ID_TIMESTAMP_RESOLUTION
+// and SYS_TYPES_FILE.
+type Layout struct {
+	TimestampDigits      int
+	SysTypeDigits        int
+	TailDigits           int
+	MaxAllowedMultiplier int
+	Clock                Clock
+	// SysTypes are DefaultSysTypes if nil.
+	SysTypes *SysTypes
+	// Obfuscator permutes formatted and packed ids if it is set, see ID_OBFUSCATION_KEY.
+	Obfuscator *Obfuscator
+	// CheckDigit is appended to formatted ids if it is set, see ID_CHECK_DIGIT. Packed ids don't have it.
+	CheckDigit *CheckDigit
+}
+
+// DefaultLayout matches FREE_DIGITS_FOR_IDS=7 and MAX_ALLOWED_MULTIPLIER=10000.
+var DefaultLayout = Layout{
+	TimestampDigits:      defaultTimestampDigits,
+	SysTypeDigits:        defaultSysTypeDigits,
+	TailDigits:           7,
+	MaxAllowedMultiplier: 10000,
+	Clock:                DefaultClock,
+	SysTypes:             DefaultSysTypes,
+}
+
+// ParseLayout converts values of env variables to the Layout and validates it.
+// Empty timestamp and sys type widths fall back to defaults.
+func ParseLayout(
+	timestampDigitsStr, sysTypeDigitsStr, freeDigitsForIdsStr, maxAllowedMultiplierStr string, clock Clock, sysTypes *SysTypes,
+) (Layout, error) {
+	layout := Layout{
+		TimestampDigits: defaultTimestampDigits,
+		SysTypeDigits:   defaultSysTypeDigits,
+		Clock:           clock,
+		SysTypes:        sysTypes,
+	}
+	var err error
+
+	if timestampDigitsStr != "" {
+		if layout.TimestampDigits, err = strconv.Atoi(timestampDigitsStr); err != nil {
+			return Layout{}, fmt.Errorf("failed to convert to int ID_TIMESTAMP_DIGITS")
+		}
+	}
+
+	if sysTypeDigitsStr != "" {
+		if layout.SysTypeDigits, err = strconv.Atoi(sysTypeDigitsStr); err != nil {
+			return Layout{}, fmt.Errorf("failed to convert to int ID_SYS_TYPE_DIGITS")
+		}
+	}
+
+	if layout.TailDigits, err = strconv.Atoi(freeDigitsForIdsStr); err != nil {
+		return Layout{}, fmt.Errorf("failed to convert to int FREE_DIGITS_FOR_IDS")
+	}
+
+	if layout.MaxAllowedMultiplier, err = strconv.Atoi(maxAllowedMultiplierStr); err != nil {
+		return Layout{}, fmt.Errorf("failed to convert to int MAX_ALLOWED_MULTIPLIER")
+	}
+
+	return layout, layout.Validate()
+}
+
+// Validate checks that every field of id fits its width.
+func (l Layout) Validate() error {
+	if l.SysTypeDigits < 1 || l.SysTypeDigits > maxSysTypeDigits {
+		return fmt.Errorf("ID_SYS_TYPE_DIGITS must be between 1 and %d", maxSysTypeDigits)
+	}
+
+	if maxDigit := l.GetSysTypes().MaxDigit(); int64(maxDigit) >= pow10(l.SysTypeDigits) {
+		return fmt.Errorf("sys type digit %d overflows ID_SYS_TYPE_DIGITS", maxDigit)
+	}
+
+	if l.TailDigits < 1 || l.TailDigits > maxTailDigits {
+		return fmt.Errorf("FREE_DIGITS_FOR_IDS must be between 1 and %d", maxTailDigits)
+	}
+
+	if l.MaxAllowedMultiplier < 1 {
+		return fmt.Errorf("MAX_ALLOWED_MULTIPLIER must be positive")
+	}
+
+	maxNumberOfIds := int(math.Pow10(l.TailDigits))
+	if maxNumberOfIds < l.MaxAllowedMultiplier {
+		return fmt.Errorf("10^(FREE_DIGITS_FOR_IDS) must not be less than MAX_ALLOWED_MULTIPLIER")
+	}
+
+	if l.TimestampDigits < 1 || l.TimestampDigits > 18 {
+		return fmt.Errorf("ID_TIMESTAMP_DIGITS must be between 1 and 18")
+	}
+
+	if err := l.Clock.Validate(); err != nil {
+		return err
+	}
+
+	if timestamp := l.Clock.Now(); timestamp >= pow10(l.TimestampDigits) {
+		return fmt.Errorf("current timestamp %d overflows ID_TIMESTAMP_DIGITS with resolution %v", timestamp, l.Clock.Resolution)
+	}
+
+	if l.Obfuscator != nil {
+		return validateObfuscatedWidth(l.bodyLength())
+	}
+
+	return nil
+}
+
+// GetSysTypes returns sys types of the layout.
+func (l Layout) GetSysTypes() *SysTypes {
+	if l.SysTypes == nil {
+		return DefaultSysTypes
+	}
+
+	return l.SysTypes
+}
+
+// Length returns number of digits in id, including the check digit.
+func (l Layout) Length() int {
+	if l.CheckDigit != nil {
+		return l.bodyLength() + 1
+	}
+
+	return l.bodyLength()
+}
+
+// bodyLength returns number of digits of id fields.
+func (l Layout) bodyLength() int {
+	return l.TimestampDigits + l.SysTypeDigits + l.TailDigits
+}
+
+// Format composes id of its fields. It fails if any field overflows its width.
+func (l Layout) Format(timestamp int64, sysTypeDigit int8, tail int32) (string, error) {
+	if timestamp < 0 || timestamp >= pow10(l.TimestampDigits) {
+		return "", fmt.Errorf("timestamp %d overflows %d digits", timestamp, l.TimestampDigits)
+	}
+
+	if sysTypeDigit < 0 || int64(sysTypeDigit) >= pow10(l.SysTypeDigits) {
+		return "", fmt.Errorf("sys type %d overflows %d digits", sysTypeDigit, l.SysTypeDigits)
+	}
+
+	if tail < 0 || int64(tail) >= pow10(l.TailDigits) {
+		return "", fmt.Errorf("tail %d overflows %d digits", tail, l.TailDigits)
+	}
+
+	id := fmt.Sprintf(
+		"%0*d%0*d%0*d",
+		l.TimestampDigits, timestamp, l.SysTypeDigits, sysTypeDigit, l.TailDigits, tail,
+	)
+
+	var err error
+	if l.Obfuscator != nil {
+		if id, err = l.Obfuscator.Obfuscate(id); err != nil {
+			return "", err
+		}
+	}
+
+	if l.CheckDigit != nil {
+		return l.CheckDigit.Append(id)
+	}
+
+	return id, nil
+}
+
+// Parse decodes id issued with DefaultLayout.
+func Parse(id string) (ID, error) {
+	return DefaultLayout.Parse(id)
+}
+
+// ValidateId checks id issued with DefaultLayout.
+func ValidateId(id string) error {
+	return DefaultLayout.ValidateId(id)
+}
+
+// BlockSize returns number of ids in one block given out with a multiplier. It is rounded down,
+// so the last id of the last block always fits the tail.
+func (l Layout) BlockSize() int {
+	return int(math.Pow10(l.TailDigits)) / l.MaxAllowedMultiplier
+}
+
+// TimestampBits returns number of bits left for timestamp in ids packed into int64.
+// Sys type and tail take as many bits as their widest decimal value needs, the sign bit is never used.
+func (l Layout) TimestampBits() int {
+	return 63 - l.sysTypeBits() - l.tailBits()
+}
+
+// PackHorizon returns the time, since which timestamps overflow TimestampBits and ids can't be packed into int64.
+func (l Layout) PackHorizon() time.Time {
+	limit := int64(1) << l.TimestampBits()
+	if l.Clock.IsMillis() {
+		return time.UnixMilli(l.Clock.Epoch.UnixMilli() + limit).UTC()
+	}
+
+	return time.Unix(l.Clock.Epoch.Unix()+limit, 0).UTC()
+}
+
+// ValidatePack checks that the current timestamp fits TimestampBits, so ids can be packed into int64.
+// Validate doesn't check it, because layouts, which are never packed, can use wider timestamps.
+func (l Layout) ValidatePack() error {
+	if timestamp := l.Clock.Now(); bits.Len64(uint64(timestamp)) > l.TimestampBits() {
+		return fmt.Errorf(
+			"current timestamp %d overflows %d bits of int64 id with resolution %v, ids could be packed till %v",
+			timestamp, l.TimestampBits(), l.Clock.Resolution, l.PackHorizon().Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+func (l Layout) sysTypeBits() int {
+	return bits.Len64(uint64(pow10(l.SysTypeDigits) - 1))
+}
+
+func (l Layout) tailBits() int {
+	return bits.Len64(uint64(pow10(l.TailDigits) - 1))
+}
+
+// Pack composes id of its fields into int64: timestamp | sys type | tail, from high bits to low ones.
+// Packed ids sort by time the same way as formatted ones.
+func (l Layout) Pack(timestamp int64, sysTypeDigit int8, tail int32) (int64, error) {
+	if timestamp < 0 || bits.Len64(uint64(timestamp)) > l.TimestampBits() {
+		return 0, fmt.Errorf("timestamp %d overflows %d bits of int64 id", timestamp, l.TimestampBits())
+	}
+
+	if sysTypeDigit < 0 || int64(sysTypeDigit) >= pow10(l.SysTypeDigits) {
+		return 0, fmt.Errorf("sys type %d overflows %d digits", sysTypeDigit, l.SysTypeDigits)
+	}
+
+	if tail < 0 || int64(tail) >= pow10(l.TailDigits) {
+		return 0, fmt.Errorf("tail %d overflows %d digits", tail, l.TailDigits)
+	}
+
+	id := timestamp<<(l.sysTypeBits()+l.tailBits()) | int64(sysTypeDigit)<<l.tailBits() | int64(tail)
+
+	if l.Obfuscator != nil {
+		return l.Obfuscator.ObfuscateInt64(id)
+	}
+
+	return id, nil
+}
+
+// Unpack returns fields of id packed with Pack.
+func (l Layout) Unpack(id int64) (timestamp int64, sysTypeDigit int8, tail int32, err error) {
+	if id < 0 {
+		return 0, 0, 0, fmt.Errorf("int64 id must not be negative")
+	}
+
+	if l.Obfuscator != nil {
+		if id, err = l.Obfuscator.DeobfuscateInt64(id); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	tailMask := int64(1)<<l.tailBits() - 1
+	sysTypeMask := int64(1)<<l.sysTypeBits() - 1
+
+	timestamp = id >> (l.sysTypeBits() + l.tailBits())
+	sysTypeDigit = int8(id >> l.tailBits() & sysTypeMask)
+	tail = int32(id & tailMask)
+
+	return timestamp, sysTypeDigit, tail, nil
+}
+
+// ValidateId checks that id has the layout: its width, check digit, sys type and tail.
+func (l Layout) ValidateId(id string) error {
+	_, err := l.Parse(id)
+	return err
+}
+
+// Parse decodes id. Check digit is verified, obfuscated ids are deobfuscated first.
+func (l Layout) Parse(id string) (ID, error) {
+	idLength := l.Length()
+	if len(id) != idLength {
+		return ID{}, fmt.Errorf("id must consist of %d digits, got %d", idLength, len(id))
+	}
+
+	for _, char := range id {
+		if char < '0' || char > '9' {
+			return ID{}, fmt.Errorf("id must consist of digits only, got %q", char)
+		}
+	}
+
+	var err error
+	if l.CheckDigit != nil {
+		if id, err = l.CheckDigit.Strip(id); err != nil {
+			return ID{}, err
+		}
+	}
+
+	if l.Obfuscator != nil {
+		if id, err = l.Obfuscator.Deobfuscate(id); err != nil {
+			return ID{}, err
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(id[:l.TimestampDigits], 10, 64)
+	if err != nil {
+		return ID{}, fmt.Errorf("failed to parse timestamp of id: %v", err)
+	}
+
+	sysTypeValue, err := strconv.Atoi(id[l.TimestampDigits : l.TimestampDigits+l.SysTypeDigits])
+	if err != nil {
+		return ID{}, fmt.Errorf("failed to parse sys type of id: %v", err)
+	}
+
+	sysTypeDigit := int8(sysTypeValue)
+	sysType, err := l.GetSysTypes().Name(sysTypeDigit)
+	if err != nil {
+		return ID{}, err
+	}
+
+	tail, err := strconv.Atoi(id[l.TimestampDigits+l.SysTypeDigits:])
+	if err != nil {
+		return ID{}, fmt.Errorf("failed to parse tail of id: %v", err)
+	}
+
+	blockSize := l.BlockSize()
+	multiplier := tail/blockSize + 1
+	if multiplier > l.MaxAllowedMultiplier {
+		return ID{}, fmt.Errorf("tail of id %d is out of range of MAX_ALLOWED_MULTIPLIER", tail)
+	}
+
+	return ID{
+		Timestamp:    timestamp,
+		Time:         l.Clock.Time(timestamp),
+		SysType:      sysType,
+		SysTypeDigit: sysTypeDigit,
+		Multiplier:   int32(multiplier),
+		Offset:       int32(tail % blockSize),
+	}, nil
+}
+
+func pow10(n int) int64 {
+	return int64(pow(10, n))
+}
+
+func pow(base uint64, n int) uint64 {
+	result := uint64(1)
+	for range n {
+		result *= base
+	}
+
+	return result
+}
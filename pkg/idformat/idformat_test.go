@@ -0,0 +1,551 @@
+package idformat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	decodedId, err := Parse("179231546351234567")
+	if err != nil {
+		t.Fatalf("failed to parse id: %v", err)
+	}
+
+	expected := ID{Timestamp: 1792315463, Time: time.Unix(1792315463, 0).UTC(), SysType: "Box", SysTypeDigit: 5, Multiplier: 1235, Offset: 567}
+	if decodedId != expected {
+		t.Errorf("expected %+v, got %+v", expected, decodedId)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, id := range []string{
+		"",
+		"17923154635123456",
+		"1792315463512345678",
+		"17923154635123456a",
+		"-79231546351234567",
+	} {
+		if _, err := Parse(id); err == nil {
+			t.Errorf("expected error for malformed id %q", id)
+		}
+	}
+}
+
+func TestLayoutFormatAndParse(t *testing.T) {
+	layout, err := ParseLayout("11", "2", "8", "1000", DefaultClock, nil)
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+
+	id, err := layout.Format(1792315463, 9, 99999999)
+	if err != nil {
+		t.Fatalf("failed to format id: %v", err)
+	}
+
+	if id != "017923154630999999999" {
+		t.Errorf("unexpected id: %s", id)
+	}
+
+	decodedId, err := layout.Parse(id)
+	if err != nil {
+		t.Fatalf("failed to parse id: %v", err)
+	}
+
+	expected := ID{Timestamp: 1792315463, Time: time.Unix(1792315463, 0).UTC(), SysType: "Clients", SysTypeDigit: 9, Multiplier: 1000, Offset: 99999}
+	if decodedId != expected {
+		t.Errorf("expected %+v, got %+v", expected, decodedId)
+	}
+
+	if _, err := layout.Format(1792315463, 9, 100000000); err == nil {
+		t.Errorf("expected error for tail overflowing its field")
+	}
+}
+
+func TestInvalidLayouts(t *testing.T) {
+	for _, layout := range []Layout{
+		{TimestampDigits: 9, SysTypeDigits: 1, TailDigits: 7, MaxAllowedMultiplier: 10000, Clock: DefaultClock},
+		{TimestampDigits: 10, SysTypeDigits: 0, TailDigits: 7, MaxAllowedMultiplier: 10000, Clock: DefaultClock},
+		{TimestampDigits: 10, SysTypeDigits: 1, TailDigits: 3, MaxAllowedMultiplier: 10000, Clock: DefaultClock},
+		{TimestampDigits: 10, SysTypeDigits: 1, TailDigits: 10, MaxAllowedMultiplier: 10000, Clock: DefaultClock},
+		{TimestampDigits: 10, SysTypeDigits: 1, TailDigits: 7, MaxAllowedMultiplier: 0, Clock: DefaultClock},
+	} {
+		if err := layout.Validate(); err == nil {
+			t.Errorf("expected error for invalid layout %+v", layout)
+		}
+	}
+}
+
+func TestMillisClockWithCustomEpoch(t *testing.T) {
+	clock, err := ParseClock("2025-01-01T00:00:00Z", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	issuedAt := time.Date(2025, 1, 2, 0, 0, 1, 500_000_000, time.UTC)
+	timestamp := clock.Timestamp(issuedAt)
+	if timestamp != 86_401_500 {
+		t.Errorf("unexpected timestamp: %d", timestamp)
+	}
+
+	if !clock.Time(timestamp).Equal(issuedAt) {
+		t.Errorf("expected time %v, got %v", issuedAt, clock.Time(timestamp))
+	}
+
+	if clock.EpochUnits() != 1_735_689_600_000 {
+		t.Errorf("unexpected epoch: %d", clock.EpochUnits())
+	}
+
+	if _, err := ParseLayout("", "", "7", "10000", DefaultClock, nil); err != nil {
+		t.Errorf("unexpected error for seconds since unix epoch: %v", err)
+	}
+
+	if _, err := ParseLayout("", "", "7", "10000", clock, nil); err == nil {
+		t.Errorf("expected error for milliseconds overflowing 10 digits of timestamp")
+	}
+
+	if _, err := ParseClock("2999-01-01T00:00:00Z", "s"); err == nil {
+		t.Errorf("expected error for epoch in the future")
+	}
+}
+
+func TestPackSortsByTime(t *testing.T) {
+	layout := DefaultLayout
+
+	earlier, err := layout.Pack(1792315463, 9, 9999999)
+	if err != nil {
+		t.Fatalf("failed to pack id: %v", err)
+	}
+
+	later, err := layout.Pack(1792315464, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to pack id: %v", err)
+	}
+
+	if earlier >= later {
+		t.Errorf("packed ids don't sort by time: %d >= %d", earlier, later)
+	}
+
+	timestamp, sysTypeDigit, tail, err := layout.Unpack(earlier)
+	if err != nil || timestamp != 1792315463 || sysTypeDigit != 9 || tail != 9999999 {
+		t.Errorf("unexpected unpacked id: %d %d %d %v", timestamp, sysTypeDigit, tail, err)
+	}
+
+	if _, err := layout.Pack(int64(1)<<layout.TimestampBits(), 0, 0); err == nil {
+		t.Errorf("expected error for timestamp overflowing %d bits", layout.TimestampBits())
+	}
+}
+
+func TestSysTypes(t *testing.T) {
+	sysTypes, err := NewSysTypes([]SysType{
+		{Name: "Payments", MinDigit: 10, MaxDigit: 10},
+		{Name: "Orders", MinDigit: 11, MaxDigit: 19},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sys types: %v", err)
+	}
+
+	for range 100 {
+		digit, err := sysTypes.Value("Orders")
+		if err != nil || digit < 11 || digit > 19 {
+			t.Fatalf("unexpected digit of Orders: %d %v", digit, err)
+		}
+
+		if name, _ := sysTypes.Name(digit); name != "Orders" {
+			t.Fatalf("expected Orders for digit %d, got %s", digit, name)
+		}
+	}
+
+	if _, err := sysTypes.Value("Vendor"); err == nil {
+		t.Errorf("expected error for unknown sys type")
+	}
+
+	if _, err := ParseLayout("", "1", "7", "10000", DefaultClock, sysTypes); err == nil {
+		t.Errorf("expected error for sys type digits overflowing 1 digit")
+	}
+
+	layout, err := ParseLayout("", "2", "7", "10000", DefaultClock, sysTypes)
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+
+	decodedId, err := layout.Parse("1792315463121234567")
+	if err != nil || decodedId.SysType != "Orders" {
+		t.Errorf("unexpected decoded id: %+v %v", decodedId, err)
+	}
+
+	invalid := map[string][]SysType{
+		"duplicate name":       {{Name: "Box", MinDigit: 0, MaxDigit: 0}, {Name: "Box", MinDigit: 1, MaxDigit: 1}},
+		"overlap":              {{Name: "Box", MinDigit: 1, MaxDigit: 8}, {Name: "Clients", MinDigit: 8, MaxDigit: 9}},
+		"reversed range":       {{Name: "Box", MinDigit: 8, MaxDigit: 1}},
+		"negative digit":       {{Name: "Box", MinDigit: -1, MaxDigit: 1}},
+		"empty name":           {{Name: "", MinDigit: 0, MaxDigit: 0}},
+		"when fill above 1":    {{Name: "Box", MinDigit: 1, MaxDigit: 8, WhenFill: 1.5}},
+		"namespace with colon": {{Name: "Box", MinDigit: 1, MaxDigit: 8, CounterNamespace: "a:b"}},
+		"no sys types set":     {},
+	}
+	for name, types := range invalid {
+		if _, err := NewSysTypes(types); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
+
+func TestLoadSysTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sys-types.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "Vendor", "digits": "0", "buffer_blocks": 2, "when_fill": 0.5, "counter_namespace": "vendor"}, {"name": "Box", "digits": "1-8"}]`), 0o644); err != nil {
+		t.Fatalf("failed to write sys types file: %v", err)
+	}
+
+	sysTypes, err := LoadSysTypes(path)
+	if err != nil {
+		t.Fatalf("failed to load sys types: %v", err)
+	}
+
+	expected := []SysType{
+		{Name: "Vendor", MinDigit: 0, MaxDigit: 0, BufferBlocks: 2, WhenFill: 0.5, CounterNamespace: "vendor"},
+		{Name: "Box", MinDigit: 1, MaxDigit: 8},
+	}
+	if list := sysTypes.List(); len(list) != len(expected) || list[0] != expected[0] || list[1] != expected[1] {
+		t.Errorf("expected %+v, got %+v", expected, list)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"name": "Box", "digits": "1-x"}]`), 0o644); err != nil {
+		t.Fatalf("failed to write sys types file: %v", err)
+	}
+
+	if _, err := LoadSysTypes(path); err == nil {
+		t.Errorf("expected error for malformed digits")
+	}
+}
+
+func TestSysTypeValueForKey(t *testing.T) {
+	digit, err := DefaultSysTypes.ValueForKey("Box", "42")
+	if err != nil || digit != 4 {
+		t.Errorf("expected digit 4 for key 42 as documented in README, got %d %v", digit, err)
+	}
+
+	for _, key := range []string{"1", "box-7", "warehouse"} {
+		first, _ := DefaultSysTypes.ValueForKey("Box", key)
+		for range 10 {
+			if digit, _ := DefaultSysTypes.ValueForKey("Box", key); digit != first {
+				t.Fatalf("key %s got digits %d and %d", key, first, digit)
+			}
+		}
+
+		if first < 1 || first > 8 {
+			t.Errorf("digit %d of key %s is out of Box range", first, key)
+		}
+	}
+
+	if digit, _ := DefaultSysTypes.ValueForKey("Vendor", "42"); digit != 0 {
+		t.Errorf("expected digit 0 of Vendor, got %d", digit)
+	}
+}
+
+func TestObfuscatorIsPermutation(t *testing.T) {
+	obfuscator, err := NewObfuscator("0123456789abcdef")
+	if err != nil {
+		t.Fatalf("failed to create obfuscator: %v", err)
+	}
+
+	seen := make(map[string]struct{}, 10000)
+	for i := range 10000 {
+		id := fmt.Sprintf("%04d", i)
+
+		obfuscatedId, err := obfuscator.Obfuscate(id)
+		if err != nil || len(obfuscatedId) != 4 {
+			t.Fatalf("unexpected obfuscated id of %s: %q %v", id, obfuscatedId, err)
+		}
+
+		if _, ok := seen[obfuscatedId]; ok {
+			t.Fatalf("obfuscated id %s is not unique", obfuscatedId)
+		}
+		seen[obfuscatedId] = struct{}{}
+
+		if deobfuscatedId, _ := obfuscator.Deobfuscate(obfuscatedId); deobfuscatedId != id {
+			t.Fatalf("expected %s after deobfuscation, got %s", id, deobfuscatedId)
+		}
+	}
+
+	for _, id := range []int64{0, 1, 481121640101447633, 1<<63 - 1} {
+		obfuscatedId, err := obfuscator.ObfuscateInt64(id)
+		if err != nil || obfuscatedId < 0 {
+			t.Fatalf("unexpected obfuscated id of %d: %d %v", id, obfuscatedId, err)
+		}
+
+		if deobfuscatedId, _ := obfuscator.DeobfuscateInt64(obfuscatedId); deobfuscatedId != id {
+			t.Errorf("expected %d after deobfuscation, got %d", id, deobfuscatedId)
+		}
+	}
+
+	if _, err := NewObfuscator("short"); err == nil {
+		t.Errorf("expected error for short key")
+	}
+}
+
+func TestLayoutWithObfuscator(t *testing.T) {
+	layout := DefaultLayout
+	layout.Obfuscator, _ = NewObfuscator("0123456789abcdef")
+
+	otherLayout := DefaultLayout
+	otherLayout.Obfuscator, _ = NewObfuscator("fedcba9876543210")
+
+	plainId, _ := DefaultLayout.Format(1792315463, 5, 1234567)
+	obfuscatedId, err := layout.Format(1792315463, 5, 1234567)
+	if err != nil {
+		t.Fatalf("failed to format id: %v", err)
+	}
+
+	if obfuscatedId == plainId || len(obfuscatedId) != len(plainId) {
+		t.Errorf("unexpected obfuscated id %s of %s", obfuscatedId, plainId)
+	}
+
+	if otherId, _ := otherLayout.Format(1792315463, 5, 1234567); otherId == obfuscatedId {
+		t.Errorf("different keys must give different ids")
+	}
+
+	decodedId, err := layout.Parse(obfuscatedId)
+	if err != nil || decodedId.Timestamp != 1792315463 || decodedId.SysTypeDigit != 5 || decodedId.Multiplier != 1235 || decodedId.Offset != 567 {
+		t.Errorf("unexpected decoded id: %+v %v", decodedId, err)
+	}
+
+	packedId, err := layout.Pack(1792315463, 5, 1234567)
+	if err != nil {
+		t.Fatalf("failed to pack id: %v", err)
+	}
+
+	timestamp, sysTypeDigit, tail, err := layout.Unpack(packedId)
+	if err != nil || timestamp != 1792315463 || sysTypeDigit != 5 || tail != 1234567 {
+		t.Errorf("unexpected unpacked id: %d %d %d %v", timestamp, sysTypeDigit, tail, err)
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	known := map[string]string{"luhn": "79927398713", "damm": "5724", "verhoeff": "2363"}
+	for name, id := range known {
+		checkDigit, err := ParseCheckDigit(name)
+		if err != nil {
+			t.Fatalf("failed to parse check digit: %v", err)
+		}
+
+		if withCheckDigit, _ := checkDigit.Append(id[:len(id)-1]); withCheckDigit != id {
+			t.Errorf("expected %s with %s check digit, got %s", id, name, withCheckDigit)
+		}
+	}
+
+	for _, name := range []string{"luhn", "damm", "verhoeff"} {
+		checkDigit, _ := ParseCheckDigit(name)
+
+		id, _ := checkDigit.Append("179231546351234567")
+		if _, err := checkDigit.Strip(id); err != nil {
+			t.Fatalf("%s rejects valid id %s: %v", name, id, err)
+		}
+
+		for i := range len(id) {
+			for digit := byte('0'); digit <= '9'; digit++ {
+				if digit == id[i] {
+					continue
+				}
+
+				mistyped := id[:i] + string(digit) + id[i+1:]
+				if _, err := checkDigit.Strip(mistyped); err == nil {
+					t.Errorf("%s accepts mistyped id %s", name, mistyped)
+				}
+			}
+
+			if i+1 < len(id) && id[i] != id[i+1] {
+				transposed := id[:i] + string(id[i+1]) + string(id[i]) + id[i+2:]
+				isLuhnBlindSpot := name == "luhn" && (id[i:i+2] == "09" || id[i:i+2] == "90")
+				if _, err := checkDigit.Strip(transposed); err == nil && !isLuhnBlindSpot {
+					t.Errorf("%s accepts transposed id %s", name, transposed)
+				}
+			}
+		}
+	}
+
+	if _, err := ParseCheckDigit("crc"); err == nil {
+		t.Errorf("expected error for unknown check digit")
+	}
+}
+
+func TestLayoutWithCheckDigit(t *testing.T) {
+	layout := DefaultLayout
+	layout.CheckDigit, _ = ParseCheckDigit("damm")
+	layout.Obfuscator, _ = NewObfuscator("0123456789abcdef")
+
+	id, err := layout.Format(1792315463, 5, 1234567)
+	if err != nil || len(id) != 19 || layout.Length() != 19 {
+		t.Fatalf("unexpected id with check digit: %q %v", id, err)
+	}
+
+	if err := layout.ValidateId(id); err != nil {
+		t.Errorf("valid id is rejected: %v", err)
+	}
+
+	decodedId, err := layout.Parse(id)
+	if err != nil || decodedId.Timestamp != 1792315463 || decodedId.Multiplier != 1235 {
+		t.Errorf("unexpected decoded id: %+v %v", decodedId, err)
+	}
+
+	transposed := id[:3] + string(id[4]) + string(id[3]) + id[5:]
+	if id[3] != id[4] && layout.ValidateId(transposed) == nil {
+		t.Errorf("transposed id %s is accepted", transposed)
+	}
+}
+
+func TestEncodingsRoundTripAndKeepOrder(t *testing.T) {
+	ids := []string{"000000000000000000", "179231546351234567", "179231546351234568", "179231546409999999", "999999999999999999"}
+	widths := map[string]int{"base32": 12, "base58": 11, "base62": 11}
+
+	for name, width := range widths {
+		encoding, err := ParseEncoding(name)
+		if err != nil {
+			t.Fatalf("failed to parse encoding: %v", err)
+		}
+
+		previous := ""
+		for _, id := range ids {
+			encoded, err := encoding.Encode(id)
+			if err != nil || len(encoded) != width {
+				t.Fatalf("unexpected %s id of %s: %q %v", name, id, encoded, err)
+			}
+
+			if encoded <= previous {
+				t.Errorf("%s ids don't keep order: %s <= %s", name, encoded, previous)
+			}
+			previous = encoded
+
+			if decoded, err := encoding.Decode(encoded, len(id)); decoded != id {
+				t.Errorf("expected %s after %s decoding, got %s %v", id, name, decoded, err)
+			}
+		}
+	}
+
+	base32, _ := ParseEncoding("base32")
+	encoded, _ := base32.Encode("179231546351234567")
+	if decoded, err := base32.Decode(strings.ToLower(encoded), 18); decoded != "179231546351234567" {
+		t.Errorf("lowercase base32 id isn't decoded: %s %v", decoded, err)
+	}
+
+	if _, err := base32.Decode("ZZZZZZZZZZZZ", 18); err == nil {
+		t.Errorf("expected error for base32 id out of range of 18 digits")
+	}
+
+	base58, _ := ParseEncoding("base58")
+	if _, err := base58.Decode("0OIl0OIl0OI", 18); err == nil {
+		t.Errorf("expected error for characters out of base58 alphabet")
+	}
+
+	if encoding, err := ParseEncoding("decimal"); encoding != nil || err != nil {
+		t.Errorf("expected no encoding for decimal, got %v %v", encoding, err)
+	}
+
+	if _, err := ParseEncoding("base64"); err == nil {
+		t.Errorf("expected error for unknown encoding")
+	}
+}
+
+func TestUUIDv7AndULID(t *testing.T) {
+	layout := DefaultLayout
+
+	// 1792315463000 ms, version 7, tail 1234567 and sys type 5 in place of random bits, variant 0b10
+	uuid, err := layout.UUIDv7(1792315463, 5, 1234567)
+	if err != nil || uuid != "01a14e53-9558-7004-ad68-705000000000" {
+		t.Errorf("unexpected uuid: %s %v", uuid, err)
+	}
+
+	ulid, err := layout.ULID(1792315463, 5, 1234567)
+	if err != nil || ulid != "01M57575AR015NM70M00000000" {
+		t.Errorf("unexpected ulid: %s %v", ulid, err)
+	}
+
+	expected, _ := layout.Format(1792315463, 5, 1234567)
+	expectedId, _ := layout.Parse(expected)
+
+	if id, err := layout.ParseUUIDv7(uuid); id != expectedId {
+		t.Errorf("expected %+v after uuid decoding, got %+v %v", expectedId, id, err)
+	}
+
+	if id, err := layout.ParseULID(strings.ToLower(ulid)); id != expectedId {
+		t.Errorf("expected %+v after ulid decoding, got %+v %v", expectedId, id, err)
+	}
+
+	earlier, _ := layout.UUIDv7(1792315463, 9, 1234567)
+	later, _ := layout.UUIDv7(1792315463, 0, 1234568)
+	if earlier >= later {
+		t.Errorf("uuids don't sort by tail: %s >= %s", earlier, later)
+	}
+
+	earlier, _ = layout.ULID(1792315463, 9, 9999999)
+	later, _ = layout.ULID(1792315464, 0, 0)
+	if earlier >= later {
+		t.Errorf("ulids don't sort by time: %s >= %s", earlier, later)
+	}
+
+	if _, err := layout.ParseUUIDv7("01a14e53-9558-4004-ad68-705000000000"); err == nil {
+		t.Errorf("expected error for uuid of version 4")
+	}
+
+	if _, err := layout.ParseUUIDv7("01a14e53-9558-7004-ad68-705000000001"); err == nil {
+		t.Errorf("expected error for uuid with random bits")
+	}
+
+	if _, err := layout.ParseUUIDv7("01a14e53-9559-7004-ad68-705000000000"); err == nil {
+		t.Errorf("expected error for uuid with milliseconds out of resolution of timestamps")
+	}
+
+	if _, err := layout.ParseULID("81M57575AR015NM70M00000000"); err == nil {
+		t.Errorf("expected error for ulid overflowing 128 bits")
+	}
+}
+
+func TestPackWithMillisClock(t *testing.T) {
+	clock, err := ParseClock("2025-01-01T00:00:00Z", "ms")
+	if err != nil {
+		t.Fatalf("failed to parse clock: %v", err)
+	}
+
+	// 35 bits of timestamp last for about 397 days since the epoch
+	layout, err := ParseLayout("12", "", "7", "10000", clock, nil)
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+
+	if horizon := layout.PackHorizon(); !horizon.Equal(clock.Time(int64(1) << 35)) {
+		t.Errorf("unexpected horizon: %v", horizon)
+	}
+
+	if err := layout.ValidatePack(); err == nil {
+		t.Errorf("expected error for milliseconds overflowing %d bits of timestamp", layout.TimestampBits())
+	}
+
+	if _, err := layout.Pack(clock.Now(), 0, 0); err == nil {
+		t.Errorf("expected error for packing current timestamp")
+	}
+
+	// 5 free digits leave 42 bits, which last for about 139 years
+	if layout, err = ParseLayout("12", "", "5", "100", clock, nil); err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+
+	if err := layout.ValidatePack(); err != nil {
+		t.Errorf("unexpected error for %d bits of timestamp: %v", layout.TimestampBits(), err)
+	}
+
+	id, err := layout.Pack(clock.Now(), 9, 99999)
+	if err != nil {
+		t.Fatalf("failed to pack id: %v", err)
+	}
+
+	if timestamp, sysTypeDigit, tail, err := layout.Unpack(id); err != nil || sysTypeDigit != 9 || tail != 99999 || timestamp > clock.Now() {
+		t.Errorf("unexpected unpacked id: %d %d %d %v", timestamp, sysTypeDigit, tail, err)
+	}
+
+	if err := DefaultLayout.ValidatePack(); err != nil {
+		t.Errorf("unexpected error for seconds since unix epoch: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package idformat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strconv"
+	"sync"
+)
+
+const (
+	// feistelRounds is even, so halves of the result have the same widths as halves of the input.
+	feistelRounds = 10
+
+	minObfuscationKeyLength = 16
+	// maxHalfDigits keeps every half of decimal id within uint64.
+	maxHalfDigits = 18
+	// int64Bits is the width of int64 ids, the sign bit is never used.
+	int64Bits = 63
+)
+
+// Obfuscator is a keyed format-preserving permutation of ids: a decimal id of N digits is turned into another
+// decimal id of N digits, an int64 id into another non negative int64 id, so public ids look random,
+// but stay unique and reversible with the same key.
+//
+// It is a Feistel network in the way of NIST FF1: the id is split into halves A and B of u and v digits
+// (or bits for int64 ids), then in every round i C = (A + F(i, B)) mod radix^m, A = B and B = C, where m is
+// u in even rounds and v in odd ones. F is HMAC-SHA256 of the round, widths of halves and B.
+//
+// It is safe for concurrent use.
+type Obfuscator struct {
+	key []byte
+	// macs keep HMAC keyed with the key, so it is only reset for every id.
+	macs sync.Pool
+}
+
+func NewObfuscator(key string) (*Obfuscator, error) {
+	if len(key) < minObfuscationKeyLength {
+		return nil, fmt.Errorf("obfuscation key must be at least %d bytes long", minObfuscationKeyLength)
+	}
+
+	o := &Obfuscator{key: []byte(key)}
+	o.macs.New = func() any {
+		return hmac.New(sha256.New, o.key)
+	}
+
+	return o, nil
+}
+
+// feistel is the permutation of the domain radix^(u+v). Its mac must be put back with release.
+type feistel struct {
+	mac        hash.Hash
+	radix      uint64
+	u, v       int
+	modU, modV uint64
+}
+
+func (o *Obfuscator) feistel(radix uint64, width int) feistel {
+	u := width / 2
+	v := width - u
+
+	return feistel{
+		mac:   o.macs.Get().(hash.Hash),
+		radix: radix,
+		u:     u,
+		v:     v,
+		modU:  pow(radix, u),
+		modV:  pow(radix, v),
+	}
+}
+
+func (o *Obfuscator) release(f feistel) {
+	o.macs.Put(f.mac)
+}
+
+func (f feistel) round(i int, b uint64, mod uint64) uint64 {
+	var input [12]byte
+	input[0] = byte(i)
+	input[1] = byte(f.radix)
+	input[2] = byte(f.u)
+	input[3] = byte(f.v)
+	binary.BigEndian.PutUint64(input[4:], b)
+
+	f.mac.Reset()
+	f.mac.Write(input[:])
+
+	return binary.BigEndian.Uint64(f.mac.Sum(nil)) % mod
+}
+
+func (f feistel) encrypt(a, b uint64) (uint64, uint64) {
+	for i := range feistelRounds {
+		mod := f.modU
+		if i%2 == 1 {
+			mod = f.modV
+		}
+
+		a, b = b, (a+f.round(i, b, mod))%mod
+	}
+
+	return a, b
+}
+
+func (f feistel) decrypt(a, b uint64) (uint64, uint64) {
+	for i := feistelRounds - 1; i >= 0; i-- {
+		mod := f.modU
+		if i%2 == 1 {
+			mod = f.modV
+		}
+
+		a, b = (b+mod-f.round(i, a, mod))%mod, a
+	}
+
+	return a, b
+}
+
+// Obfuscate permutes decimal id of any width from 2 to 36 digits.
+func (o *Obfuscator) Obfuscate(id string) (string, error) {
+	return o.permuteDecimal(id, feistel.encrypt)
+}
+
+// Deobfuscate returns decimal id obfuscated with Obfuscate.
+func (o *Obfuscator) Deobfuscate(id string) (string, error) {
+	return o.permuteDecimal(id, feistel.decrypt)
+}
+
+func (o *Obfuscator) permuteDecimal(id string, permute func(feistel, uint64, uint64) (uint64, uint64)) (string, error) {
+	if err := validateObfuscatedWidth(len(id)); err != nil {
+		return "", err
+	}
+
+	f := o.feistel(10, len(id))
+	defer o.release(f)
+
+	a, err := strconv.ParseUint(id[:f.u], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("id must consist of digits only: %s", id)
+	}
+
+	b, err := strconv.ParseUint(id[f.u:], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("id must consist of digits only: %s", id)
+	}
+
+	a, b = permute(f, a, b)
+
+	return fmt.Sprintf("%0*d%0*d", f.u, a, f.v, b), nil
+}
+
+// ObfuscateInt64 permutes non negative int64 id.
+func (o *Obfuscator) ObfuscateInt64(id int64) (int64, error) {
+	return o.permuteInt64(id, feistel.encrypt)
+}
+
+// DeobfuscateInt64 returns int64 id obfuscated with ObfuscateInt64.
+func (o *Obfuscator) DeobfuscateInt64(id int64) (int64, error) {
+	return o.permuteInt64(id, feistel.decrypt)
+}
+
+func (o *Obfuscator) permuteInt64(id int64, permute func(feistel, uint64, uint64) (uint64, uint64)) (int64, error) {
+	if id < 0 {
+		return 0, fmt.Errorf("int64 id must not be negative")
+	}
+
+	f := o.feistel(2, int64Bits)
+	defer o.release(f)
+
+	a, b := permute(f, uint64(id)>>f.v, uint64(id)&(f.modV-1))
+
+	return int64(a<<f.v | b), nil
+}
+
+func validateObfuscatedWidth(width int) error {
+	if width < 2 || width > 2*maxHalfDigits {
+		return fmt.Errorf("obfuscated id must consist of 2 to %d digits, got %d", 2*maxHalfDigits, width)
+	}
+
+	return nil
+}
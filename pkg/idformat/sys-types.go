@@ -0,0 +1,217 @@
+package idformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SysType maps name of a system to the digit or range of digits its ids get.
+// Ids of a system with range get random digit of it.
+type SysType struct {
+	Name     string `json:"name"`
+	MinDigit int8   `json:"min_digit"`
+	MaxDigit int8   `json:"max_digit"`
+
+	// BufferBlocks is number of blocks buffered for the sys type, 0 means default of one block.
+	BufferBlocks int `json:"buffer_blocks,omitempty"`
+	// WhenFill is share of the buffer, below which it is refilled, 0 means default of the storage.
+	WhenFill float64 `json:"when_fill,omitempty"`
+	// CounterNamespace gives the sys type its own counter of blocks, empty namespace is shared by all sys types.
+	CounterNamespace string `json:"counter_namespace,omitempty"`
+}
+
+// SysTypes is a registry of sys types with non overlapping digits.
+type SysTypes struct {
+	types   []SysType
+	byName  map[string]SysType
+	byDigit map[int8]SysType
+}
+
+// DefaultSysTypes are Vendor=0, Box=1-8 and Clients=9.
+var DefaultSysTypes, _ = NewSysTypes([]SysType{
+	{Name: "Vendor", MinDigit: 0, MaxDigit: 0},
+	{Name: "Box", MinDigit: 1, MaxDigit: 8},
+	{Name: "Clients", MinDigit: 9, MaxDigit: 9},
+})
+
+// NewSysTypes validates sys types: names must be unique, digits must be non negative and must not overlap.
+func NewSysTypes(types []SysType) (*SysTypes, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("at least one sys type must be specified")
+	}
+
+	sysTypes := &SysTypes{
+		types:   make([]SysType, len(types)),
+		byName:  make(map[string]SysType, len(types)),
+		byDigit: make(map[int8]SysType),
+	}
+	copy(sysTypes.types, types)
+	sort.Slice(sysTypes.types, func(i, j int) bool { return sysTypes.types[i].MinDigit < sysTypes.types[j].MinDigit })
+
+	for _, sysType := range sysTypes.types {
+		if sysType.Name == "" {
+			return nil, fmt.Errorf("name of sys type must not be empty")
+		}
+
+		if _, ok := sysTypes.byName[sysType.Name]; ok {
+			return nil, fmt.Errorf("sys type %s is specified more than once", sysType.Name)
+		}
+
+		if sysType.MinDigit < 0 || sysType.MinDigit > sysType.MaxDigit {
+			return nil, fmt.Errorf("digits of sys type %s must be a non negative range, got %d-%d", sysType.Name, sysType.MinDigit, sysType.MaxDigit)
+		}
+
+		if sysType.BufferBlocks < 0 {
+			return nil, fmt.Errorf("buffer blocks of sys type %s must not be negative, got %d", sysType.Name, sysType.BufferBlocks)
+		}
+
+		if sysType.WhenFill < 0 || sysType.WhenFill > 1 {
+			return nil, fmt.Errorf("when fill of sys type %s must be between 0 and 1, got %v", sysType.Name, sysType.WhenFill)
+		}
+
+		if strings.ContainsAny(sysType.CounterNamespace, " {}:/") {
+			return nil, fmt.Errorf("counter namespace of sys type %s must not contain spaces, braces, colons or slashes: %s", sysType.Name, sysType.CounterNamespace)
+		}
+
+		for digit := sysType.MinDigit; ; digit++ {
+			if other, ok := sysTypes.byDigit[digit]; ok {
+				return nil, fmt.Errorf("digit %d of sys type %s overlaps with sys type %s", digit, sysType.Name, other.Name)
+			}
+
+			sysTypes.byDigit[digit] = sysType
+
+			if digit == sysType.MaxDigit {
+				break
+			}
+		}
+
+		sysTypes.byName[sysType.Name] = sysType
+	}
+
+	return sysTypes, nil
+}
+
+type sysTypeConfig struct {
+	Name string `json:"name"`
+	// Digits is a digit, e.g. "0", or an inclusive range of digits, e.g. "1-8".
+	Digits           string  `json:"digits"`
+	BufferBlocks     int     `json:"buffer_blocks"`
+	WhenFill         float64 `json:"when_fill"`
+	CounterNamespace string  `json:"counter_namespace"`
+}
+
+// LoadSysTypes reads sys types from JSON file, e.g. [{"name": "Vendor", "digits": "0"}, {"name": "Box", "digits": "1-8"}].
+// Buffer of a sys type is configured with optional buffer_blocks, when_fill and counter_namespace.
+func LoadSysTypes(path string) (*SysTypes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sys types file: %v", err)
+	}
+
+	var configs []sysTypeConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse sys types file: %v", err)
+	}
+
+	types := make([]SysType, len(configs))
+	for i, config := range configs {
+		if types[i], err = parseSysType(config.Name, config.Digits); err != nil {
+			return nil, err
+		}
+
+		types[i].BufferBlocks = config.BufferBlocks
+		types[i].WhenFill = config.WhenFill
+		types[i].CounterNamespace = config.CounterNamespace
+	}
+
+	return NewSysTypes(types)
+}
+
+func parseSysType(name, digits string) (SysType, error) {
+	minDigitStr, maxDigitStr, isRange := strings.Cut(digits, "-")
+	if !isRange {
+		maxDigitStr = minDigitStr
+	}
+
+	minDigit, err := strconv.ParseInt(strings.TrimSpace(minDigitStr), 10, 8)
+	if err != nil {
+		return SysType{}, fmt.Errorf("failed to parse digits of sys type %s: %s", name, digits)
+	}
+
+	maxDigit, err := strconv.ParseInt(strings.TrimSpace(maxDigitStr), 10, 8)
+	if err != nil {
+		return SysType{}, fmt.Errorf("failed to parse digits of sys type %s: %s", name, digits)
+	}
+
+	return SysType{Name: name, MinDigit: int8(minDigit), MaxDigit: int8(maxDigit)}, nil
+}
+
+// Value returns digit for an id of the sys type.
+func (st *SysTypes) Value(name string) (int8, error) {
+	sysType, ok := st.byName[name]
+	if !ok {
+		return -1, fmt.Errorf("unknown sys_type: %s", name)
+	}
+
+	return sysType.MinDigit + int8(rand.Int32N(int32(sysType.MaxDigit-sysType.MinDigit)+1)), nil
+}
+
+// ValueForKey returns digit for an id of the sys type routed by the key, so ids with the same key always get
+// the same digit. The digit is MinDigit + FNV-1a 32-bit hash of the key modulo number of digits of the sys type.
+// Empty key gets random digit as in Value.
+func (st *SysTypes) ValueForKey(name, key string) (int8, error) {
+	if key == "" {
+		return st.Value(name)
+	}
+
+	sysType, ok := st.byName[name]
+	if !ok {
+		return -1, fmt.Errorf("unknown sys_type: %s", name)
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+
+	return sysType.MinDigit + int8(hash.Sum32()%(uint32(sysType.MaxDigit-sysType.MinDigit)+1)), nil
+}
+
+// Name returns name of the sys type the digit belongs to.
+func (st *SysTypes) Name(digit int8) (string, error) {
+	sysType, ok := st.byDigit[digit]
+	if !ok {
+		return "", fmt.Errorf("unknown sys_type value: %d", digit)
+	}
+
+	return sysType.Name, nil
+}
+
+// Has reports whether sys type with the name is registered.
+func (st *SysTypes) Has(name string) bool {
+	_, ok := st.byName[name]
+	return ok
+}
+
+// Get returns sys type by name.
+func (st *SysTypes) Get(name string) (SysType, bool) {
+	sysType, ok := st.byName[name]
+	return sysType, ok
+}
+
+// List returns sys types ordered by digits.
+func (st *SysTypes) List() []SysType {
+	types := make([]SysType, len(st.types))
+	copy(types, st.types)
+
+	return types
+}
+
+// MaxDigit returns the greatest digit of all sys types.
+func (st *SysTypes) MaxDigit() int8 {
+	return st.types[len(st.types)-1].MaxDigit
+}
@@ -0,0 +1,166 @@
+package idformat
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UUIDv7 (RFC 9562) and ULID keep unix time of the block in milliseconds in the first 48 bits. Bits, which are
+// random in the standards, get the tail and the sys type digit instead: the tail goes first, so values issued
+// within one millisecond sort by it like decimal ids do, the rest of bits are zero. Values are unique as long as
+// decimal ids are. Obfuscation and check digit don't apply to them.
+const (
+	tailBits128    = 30
+	sysTypeBits128 = 8
+
+	uuidVersion = 7
+	// uuidVariant is 0b10 of RFC 9562.
+	uuidVariant = 2
+
+	ulidLength = 26
+	// crockfordAlphabet is Crockford's base32, ULID is written with it.
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// UUIDv7 composes UUIDv7 of id fields.
+func (l Layout) UUIDv7(timestamp int64, sysTypeDigit int8, tail int32) (string, error) {
+	ms, err := l.unixMilli(timestamp, sysTypeDigit, tail)
+	if err != nil {
+		return "", err
+	}
+
+	// rand_a takes 12 high bits of tail, rand_b takes the rest of tail and the sys type digit
+	hi := ms<<16 | uuidVersion<<12 | uint64(tail)>>(tailBits128-12)
+	lo := uint64(uuidVariant)<<62 | (uint64(tail)&(1<<(tailBits128-12)-1))<<44 | uint64(sysTypeDigit)<<36
+
+	var value [16]byte
+	putUint128(value[:], hi, lo)
+
+	encoded := hex.EncodeToString(value[:])
+
+	return fmt.Sprintf("%s-%s-%s-%s-%s", encoded[:8], encoded[8:12], encoded[12:16], encoded[16:20], encoded[20:]), nil
+}
+
+// ParseUUIDv7 decodes UUIDv7 composed with UUIDv7.
+func (l Layout) ParseUUIDv7(uuid string) (ID, error) {
+	if len(uuid) != 36 || uuid[8] != '-' || uuid[13] != '-' || uuid[18] != '-' || uuid[23] != '-' {
+		return ID{}, fmt.Errorf("uuid must be in 8-4-4-4-12 hex format: %s", uuid)
+	}
+
+	value, err := hex.DecodeString(strings.ReplaceAll(uuid, "-", ""))
+	if err != nil {
+		return ID{}, fmt.Errorf("uuid must be in 8-4-4-4-12 hex format: %s", uuid)
+	}
+
+	hi, lo := getUint128(value)
+	if hi>>12&0xf != uuidVersion || lo>>62 != uuidVariant {
+		return ID{}, fmt.Errorf("uuid must be of version 7 and variant of RFC 9562")
+	}
+
+	if lo&(1<<36-1) != 0 {
+		return ID{}, fmt.Errorf("uuid wasn't issued by the generator")
+	}
+
+	tail := int32((hi&(1<<12-1))<<(tailBits128-12) | lo>>44&(1<<(tailBits128-12)-1))
+	sysTypeDigit := int8(lo >> 36 & (1<<sysTypeBits128 - 1))
+
+	return l.fromUnixMilli(hi>>16, sysTypeDigit, tail)
+}
+
+// ULID composes ULID of id fields.
+func (l Layout) ULID(timestamp int64, sysTypeDigit int8, tail int32) (string, error) {
+	ms, err := l.unixMilli(timestamp, sysTypeDigit, tail)
+	if err != nil {
+		return "", err
+	}
+
+	// 80 bits after the time are the tail and the sys type digit
+	hi := ms<<16 | uint64(tail)>>(tailBits128-16)
+	lo := (uint64(tail)&(1<<(tailBits128-16)-1))<<50 | uint64(sysTypeDigit)<<42
+
+	encoded := make([]byte, ulidLength)
+	for i := ulidLength - 1; i >= 0; i-- {
+		encoded[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(encoded), nil
+}
+
+// ParseULID decodes ULID composed with ULID. It is case-insensitive.
+func (l Layout) ParseULID(ulid string) (ID, error) {
+	if len(ulid) != ulidLength {
+		return ID{}, fmt.Errorf("ulid must consist of %d characters, got %d", ulidLength, len(ulid))
+	}
+
+	var hi, lo uint64
+	for i, char := range normalizeCrockford(ulid) {
+		index := strings.IndexRune(crockfordAlphabet, char)
+		if index == -1 || (i == 0 && index > 7) {
+			return ID{}, fmt.Errorf("ulid must be in Crockford's base32 and fit 128 bits: %s", ulid)
+		}
+
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(index)
+	}
+
+	if lo&(1<<42-1) != 0 {
+		return ID{}, fmt.Errorf("ulid wasn't issued by the generator")
+	}
+
+	tail := int32((hi&(1<<16-1))<<(tailBits128-16) | lo>>50)
+	sysTypeDigit := int8(lo >> 42 & (1<<sysTypeBits128 - 1))
+
+	return l.fromUnixMilli(hi>>16, sysTypeDigit, tail)
+}
+
+// unixMilli returns unix time of the timestamp in milliseconds and checks that fields fit their bits.
+func (l Layout) unixMilli(timestamp int64, sysTypeDigit int8, tail int32) (uint64, error) {
+	if sysTypeDigit < 0 || int64(sysTypeDigit) >= pow10(l.SysTypeDigits) {
+		return 0, fmt.Errorf("sys type %d overflows %d digits", sysTypeDigit, l.SysTypeDigits)
+	}
+
+	if tail < 0 || int64(tail) >= pow10(l.TailDigits) {
+		return 0, fmt.Errorf("tail %d overflows %d digits", tail, l.TailDigits)
+	}
+
+	ms := l.Clock.Time(timestamp).UnixMilli()
+	if ms < 0 || ms >= 1<<48 {
+		return 0, fmt.Errorf("time of timestamp %d doesn't fit 48 bits of milliseconds", timestamp)
+	}
+
+	return uint64(ms), nil
+}
+
+func (l Layout) fromUnixMilli(ms uint64, sysTypeDigit int8, tail int32) (ID, error) {
+	timestamp := l.Clock.Timestamp(time.UnixMilli(int64(ms)))
+	if uint64(l.Clock.Time(timestamp).UnixMilli()) != ms {
+		return ID{}, fmt.Errorf("time %dms isn't a timestamp of resolution %v", ms, l.Clock.Resolution)
+	}
+
+	id, err := l.Format(timestamp, sysTypeDigit, tail)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return l.Parse(id)
+}
+
+func putUint128(value []byte, hi, lo uint64) {
+	for i := range 8 {
+		value[i] = byte(hi >> (56 - 8*i))
+		value[8+i] = byte(lo >> (56 - 8*i))
+	}
+}
+
+func getUint128(value []byte) (hi, lo uint64) {
+	for i := range 8 {
+		hi = hi<<8 | uint64(value[i])
+		lo = lo<<8 | uint64(value[8+i])
+	}
+
+	return hi, lo
+}